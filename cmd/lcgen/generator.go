@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/leancloud/go-sdk/leancloud"
+)
+
+// builtinFields are fields held by leancloud.Object, ACL is exposed by leancloud.Object.ACL()
+var builtinFields = map[string]bool{
+	"objectId":  true,
+	"createdAt": true,
+	"updatedAt": true,
+	"ACL":       true,
+}
+
+// builtinUserFields are fields held by leancloud.User, or never returned to clients
+var builtinUserFields = map[string]bool{
+	"sessionToken":        true,
+	"username":            true,
+	"password":            true,
+	"email":               true,
+	"emailVerified":       true,
+	"mobilePhoneNumber":   true,
+	"mobilePhoneVerified": true,
+	"authData":            true,
+}
+
+// promotedNames are names of fields and methods promoted from the embedded leancloud.Object or leancloud.User,
+// generated fields should not be named after them, which would shadow those of the embedded struct
+var promotedNames = map[string]map[string]bool{
+	"leancloud.Object": collectPromotedNames(reflect.TypeOf(leancloud.Object{})),
+	"leancloud.User":   collectPromotedNames(reflect.TypeOf(leancloud.User{})),
+}
+
+func collectPromotedNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{
+		t.Name(): true,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath == "" {
+			names[field.Name] = true
+		}
+		if field.Anonymous {
+			for name := range collectPromotedNames(field.Type) {
+				names[name] = true
+			}
+		}
+	}
+
+	methods := reflect.PtrTo(t)
+	for i := 0; i < methods.NumMethod(); i++ {
+		names[methods.Method(i).Name] = true
+	}
+
+	return names
+}
+
+// fieldTypes maps types of schema to Go types which bind accepts
+var fieldTypes = map[string]string{
+	"String":   "string",
+	"Number":   "float64",
+	"Boolean":  "bool",
+	"Date":     "time.Time",
+	"Object":   "map[string]interface{}",
+	"Array":    "[]interface{}",
+	"GeoPoint": "leancloud.GeoPoint",
+	"File":     "*leancloud.File",
+	"Bytes":    "[]byte",
+	"Pointer":  "*leancloud.Object",
+	"ACL":      "*leancloud.ACL",
+}
+
+// parseSchemas reads schemas exported from the schema API, either a list of schemas,
+// {"results": [...]}, or fields of classes keyed by class name
+func parseSchemas(data []byte) ([]*leancloud.ClassSchema, error) {
+	var schemas []*leancloud.ClassSchema
+	if err := json.Unmarshal(data, &schemas); err == nil {
+		return schemas, nil
+	}
+
+	var results struct {
+		Results []*leancloud.ClassSchema `json:"results"`
+	}
+	if err := json.Unmarshal(data, &results); err == nil && results.Results != nil {
+		return results.Results, nil
+	}
+
+	classes := make(map[string]map[string]*leancloud.SchemaField)
+	if err := json.Unmarshal(data, &classes); err != nil {
+		return nil, fmt.Errorf("unable to parse schemas: %v", err)
+	}
+	for className, fields := range classes {
+		schemas = append(schemas, &leancloud.ClassSchema{
+			ClassName: className,
+			Fields:    fields,
+		})
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].ClassName < schemas[j].ClassName
+	})
+
+	return schemas, nil
+}
+
+// generate emits Go source of structs and field name constants of the classes
+func generate(packageName string, schemas []*leancloud.ClassSchema) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	fmt.Fprintln(buf, "// Code generated by lcgen. DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintf(buf, "package %s\n\n", packageName)
+
+	body := new(bytes.Buffer)
+	usesTime := false
+	for _, schema := range schemas {
+		if schema.ClassName == "_File" {
+			// leancloud.File is used for _File
+			continue
+		}
+
+		typeName := goName(strings.TrimPrefix(schema.ClassName, "_"))
+		embedded := "leancloud.Object"
+		if schema.ClassName == "_User" {
+			embedded = "leancloud.User"
+		}
+
+		var names []string
+		for name := range schema.Fields {
+			if builtinFields[name] || (schema.ClassName == "_User" && builtinUserFields[name]) {
+				continue
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		// field names in Go should not collide with each other or the embedded struct,
+		// while names of constants should only not collide with each other
+		fieldNames := map[string]string{}
+		constNames := map[string]string{}
+		usedFieldNames := map[string]bool{}
+		for name := range promotedNames[embedded] {
+			usedFieldNames[name] = true
+		}
+		usedConstNames := map[string]bool{}
+		for _, name := range names {
+			fieldNames[name] = uniqueName(goName(name), usedFieldNames)
+			constNames[name] = uniqueName(goName(name), usedConstNames)
+		}
+
+		fmt.Fprintf(body, "// %s is generated from the schema of class %s\n", typeName, schema.ClassName)
+		fmt.Fprintf(body, "type %s struct {\n", typeName)
+		fmt.Fprintf(body, "\t%s\n", embedded)
+		for _, name := range names {
+			field := schema.Fields[name]
+			goType, ok := fieldTypes[field.Type]
+			if !ok {
+				fmt.Fprintf(body, "\t// %s of type %s is not supported\n", name, field.Type)
+				continue
+			}
+			if goType == "time.Time" {
+				usesTime = true
+			}
+			if field.Type == "Pointer" && field.ClassName != "" {
+				fmt.Fprintf(body, "\t// %s is a Pointer to %s\n", fieldNames[name], field.ClassName)
+			}
+			fmt.Fprintf(body, "\t%s %s `json:\"%s\"`\n", fieldNames[name], goType, name)
+		}
+		fmt.Fprintf(body, "}\n\n")
+
+		fmt.Fprintf(body, "// Names of class %s and its fields\n", schema.ClassName)
+		fmt.Fprintf(body, "const (\n")
+		fmt.Fprintf(body, "\t%sClassName = %q\n", typeName, schema.ClassName)
+		for _, name := range names {
+			fmt.Fprintf(body, "\t%sField%s = %q\n", typeName, constNames[name], name)
+		}
+		fmt.Fprintf(body, ")\n\n")
+	}
+
+	fmt.Fprintln(buf, "import (")
+	if usesTime {
+		fmt.Fprintln(buf, "\t\"time\"")
+		fmt.Fprintln(buf)
+	}
+	fmt.Fprintln(buf, "\t\"github.com/leancloud/go-sdk/leancloud\"")
+	fmt.Fprintln(buf, ")")
+	fmt.Fprintln(buf)
+	buf.Write(body.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+// uniqueName suffixes the name with Field until it is not used, and marks it as used
+func uniqueName(name string, used map[string]bool) string {
+	for used[name] {
+		name = fmt.Sprint(name, "Field")
+	}
+	used[name] = true
+
+	return name
+}
+
+// goName converts names of classes and fields to exported Go identifiers, e.g. mobile_phone to MobilePhone
+func goName(name string) string {
+	var builder strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		builder.WriteRune(r)
+	}
+
+	goName := builder.String()
+	for _, initialism := range []string{"Id", "Url", "Acl"} {
+		if strings.HasSuffix(goName, initialism) {
+			goName = fmt.Sprint(strings.TrimSuffix(goName, initialism), strings.ToUpper(initialism))
+		}
+	}
+
+	if goName == "" || unicode.IsDigit([]rune(goName)[0]) {
+		goName = fmt.Sprint("X", goName)
+	}
+
+	return goName
+}
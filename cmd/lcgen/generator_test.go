@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"title":         "Title",
+		"mobile_phone":  "MobilePhone",
+		"authorId":      "AuthorID",
+		"avatarUrl":     "AvatarURL",
+		"ACL":           "ACL",
+		"2fa":           "X2fa",
+		"_Conversation": "Conversation",
+	}
+
+	for name, want := range tests {
+		if got := goName(name); got != want {
+			t.Errorf("goName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	schemas, err := parseSchemas([]byte(`{
+		"Post": {
+			"objectId": {"type": "String"},
+			"id": {"type": "String"},
+			"title": {"type": "String"},
+			"views": {"type": "Number"},
+			"mobilePhone": {"type": "String"},
+			"mobile_phone": {"type": "String"},
+			"publishedAt": {"type": "Date"},
+			"author": {"type": "Pointer", "className": "_User"},
+			"cover": {"type": "File"},
+			"location": {"type": "GeoPoint"},
+			"object": {"type": "Object"},
+			"comments": {"type": "Relation", "className": "Comment"},
+			"ACL": {"type": "ACL"}
+		},
+		"_User": {
+			"username": {"type": "String"},
+			"nickname": {"type": "String"},
+			"raw": {"type": "Object"}
+		},
+		"_File": {
+			"name": {"type": "String"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := generate("models", schemas)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// alignment of gofmt is ignored
+	normalized := strings.Join(strings.Fields(string(source)), " ")
+	for _, want := range []string{
+		"package models",
+		`"time"`,
+		"type Post struct { leancloud.Object",
+		"Title string `json:\"title\"`",
+		"Views float64 `json:\"views\"`",
+		"PublishedAt time.Time `json:\"publishedAt\"`",
+		"// Author is a Pointer to _User Author *leancloud.Object `json:\"author\"`",
+		"Cover *leancloud.File `json:\"cover\"`",
+		"Location leancloud.GeoPoint `json:\"location\"`",
+		"ObjectField map[string]interface{} `json:\"object\"`",
+		"IDField string `json:\"id\"`",
+		"PostFieldID = \"id\"",
+		"PostFieldObject = \"object\"",
+		"MobilePhone string `json:\"mobilePhone\"`",
+		"MobilePhoneField string `json:\"mobile_phone\"`",
+		"PostFieldMobilePhoneField = \"mobile_phone\"",
+		"// comments of type Relation is not supported",
+		"PostClassName = \"Post\"",
+		"PostFieldTitle = \"title\"",
+		"type User struct { leancloud.User Nickname string `json:\"nickname\"` RawField map[string]interface{} `json:\"raw\"` }",
+	} {
+		if !strings.Contains(normalized, want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, source)
+		}
+	}
+
+	if strings.Contains(string(source), "ObjectID") || strings.Contains(string(source), "ACL") || strings.Contains(string(source), "type File struct") {
+		t.Errorf("unexpected builtin fields or classes:\n%s", source)
+	}
+}
@@ -0,0 +1,87 @@
+// Command lcgen generates Go structs embedding leancloud.Object from class schemas,
+// along with constants of class and field names for use in queries.
+//
+// Schemas are fetched from the schema API with the master key in LEANCLOUD_APP_ID, LEANCLOUD_APP_KEY,
+// LEANCLOUD_APP_MASTER_KEY and LEANCLOUD_API_SERVER, or read from a file exported from it:
+//
+//	lcgen -package models -classes Post,Comment -o models/classes.go
+//	lcgen -package models -schema schemas.json -o models/classes.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/leancloud/go-sdk/leancloud"
+)
+
+func main() {
+	packageName := flag.String("package", "models", "package name of the generated file")
+	schemaFile := flag.String("schema", "", "JSON file of schemas exported from the schema API, fetch from the API if empty")
+	classes := flag.String("classes", "", "comma separated classes to generate, all classes if empty")
+	output := flag.String("o", "", "output file, stdout if empty")
+	flag.Parse()
+
+	if err := run(*packageName, *schemaFile, *classes, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "lcgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(packageName, schemaFile, classes, output string) error {
+	var schemas []*leancloud.ClassSchema
+	if schemaFile != "" {
+		data, err := ioutil.ReadFile(schemaFile)
+		if err != nil {
+			return err
+		}
+		if schemas, err = parseSchemas(data); err != nil {
+			return err
+		}
+	} else {
+		client := leancloud.NewEnvClient()
+		if classes == "" {
+			var err error
+			if schemas, err = client.Schemas.List(); err != nil {
+				return err
+			}
+		} else {
+			for _, className := range strings.Split(classes, ",") {
+				schema, err := client.Schemas.Get(strings.TrimSpace(className))
+				if err != nil {
+					return err
+				}
+				schemas = append(schemas, schema)
+			}
+		}
+	}
+
+	if classes != "" {
+		selected := make(map[string]bool)
+		for _, className := range strings.Split(classes, ",") {
+			selected[strings.TrimSpace(className)] = true
+		}
+		var selectedSchemas []*leancloud.ClassSchema
+		for _, schema := range schemas {
+			if selected[schema.ClassName] {
+				selectedSchemas = append(selectedSchemas, schema)
+			}
+		}
+		schemas = selectedSchemas
+	}
+
+	source, err := generate(packageName, schemas)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err := os.Stdout.Write(source)
+		return err
+	}
+
+	return ioutil.WriteFile(output, source, 0644)
+}
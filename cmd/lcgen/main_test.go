@@ -0,0 +1,42 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMainWithSchemaFile(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "lcgen")
+	if output, err := exec.Command("go", "build", "-o", binary, ".").CombinedOutput(); err != nil {
+		t.Fatalf("unable to build lcgen: %v\n%s", err, output)
+	}
+
+	schemaFile := filepath.Join(dir, "schemas.json")
+	if err := ioutil.WriteFile(schemaFile, []byte(`{"Post": {"title": {"type": "String"}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// schemas read from a file should need no environment variables of LeanCloud
+	var env []string
+	for _, variable := range os.Environ() {
+		if !strings.HasPrefix(variable, "LEANCLOUD_") {
+			env = append(env, variable)
+		}
+	}
+
+	cmd := exec.Command(binary, "-package", "models", "-schema", schemaFile)
+	cmd.Env = env
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error when run lcgen: %v\n%s", err, output)
+	}
+
+	if !strings.Contains(string(output), "type Post struct") {
+		t.Errorf("dismatch generated source:\n%s", output)
+	}
+}
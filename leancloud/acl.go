@@ -1,6 +1,13 @@
 package leancloud
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const aclPublicKey = "*"
+
+const aclRolePrefix = "role:"
 
 // ACL include permission group of object
 type ACL struct {
@@ -22,11 +29,11 @@ func NewACLWithUser(user *User) *ACL {
 }
 
 func (acl *ACL) SetPublicReadAccess(allowed bool) {
-	acl.set("*", "read", allowed)
+	acl.set(aclPublicKey, "read", allowed)
 }
 
 func (acl *ACL) SetPublicWriteAccess(allowed bool) {
-	acl.set("*", "write", allowed)
+	acl.set(aclPublicKey, "write", allowed)
 }
 
 func (acl *ACL) SetWriteAccess(user *User, allowed bool) {
@@ -38,19 +45,35 @@ func (acl *ACL) SetReadAccess(user *User, allowed bool) {
 }
 
 func (acl *ACL) SetRoleReadAccess(role *Role, allowed bool) {
-	acl.set(fmt.Sprint("role:", role.Name), "read", allowed)
+	acl.SetRoleReadAccessByName(role.Name, allowed)
 }
 
 func (acl *ACL) SetRoleWriteAccess(role *Role, allowed bool) {
-	acl.set(fmt.Sprint("role:", role.Name), "write", allowed)
+	acl.SetRoleWriteAccessByName(role.Name, allowed)
+}
+
+func (acl *ACL) SetReadAccessByID(userID string, allowed bool) {
+	acl.set(userID, "read", allowed)
+}
+
+func (acl *ACL) SetWriteAccessByID(userID string, allowed bool) {
+	acl.set(userID, "write", allowed)
+}
+
+func (acl *ACL) SetRoleReadAccessByName(roleName string, allowed bool) {
+	acl.set(fmt.Sprint(aclRolePrefix, roleName), "read", allowed)
+}
+
+func (acl *ACL) SetRoleWriteAccessByName(roleName string, allowed bool) {
+	acl.set(fmt.Sprint(aclRolePrefix, roleName), "write", allowed)
 }
 
 func (acl *ACL) GetPublicReadAccess() bool {
-	return acl.get("*", "read")
+	return acl.get(aclPublicKey, "read")
 }
 
 func (acl *ACL) GetPublicWriteAccess() bool {
-	return acl.get("*", "write")
+	return acl.get(aclPublicKey, "write")
 }
 
 func (acl *ACL) GetReadAccess(user *User) bool {
@@ -62,14 +85,108 @@ func (acl *ACL) GetWriteAccess(user *User) bool {
 }
 
 func (acl *ACL) GetRoleReadAccess(role *Role) bool {
-	return acl.get(fmt.Sprint("role:", role.Name), "read")
+	return acl.GetRoleReadAccessByName(role.Name)
 }
 
 func (acl *ACL) GetRoleWriteAccess(role *Role) bool {
-	return acl.get(fmt.Sprint("role:", role.Name), "write")
+	return acl.GetRoleWriteAccessByName(role.Name)
+}
+
+func (acl *ACL) GetReadAccessByID(userID string) bool {
+	return acl.get(userID, "read")
+}
+
+func (acl *ACL) GetWriteAccessByID(userID string) bool {
+	return acl.get(userID, "write")
+}
+
+func (acl *ACL) GetRoleReadAccessByName(roleName string) bool {
+	return acl.get(fmt.Sprint(aclRolePrefix, roleName), "read")
+}
+
+func (acl *ACL) GetRoleWriteAccessByName(roleName string) bool {
+	return acl.get(fmt.Sprint(aclRolePrefix, roleName), "write")
+}
+
+// CanRead reports whether the user, or anyone if user is nil, with roles is allowed to read by the ACL
+func (acl *ACL) CanRead(user *User, roles ...*Role) bool {
+	return acl.can("read", user, roles)
+}
+
+// CanWrite reports whether the user, or anyone if user is nil, with roles is allowed to write by the ACL
+func (acl *ACL) CanWrite(user *User, roles ...*Role) bool {
+	return acl.can("write", user, roles)
+}
+
+func (acl *ACL) can(perm string, user *User, roles []*Role) bool {
+	if acl.get(aclPublicKey, perm) {
+		return true
+	}
+
+	if user == nil {
+		return false
+	}
+
+	if user.ID != "" && acl.get(user.ID, perm) {
+		return true
+	}
+
+	for _, role := range roles {
+		if role != nil && acl.get(fmt.Sprint(aclRolePrefix, role.Name), perm) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MarshalJSON encodes the ACL in the form of LeanCloud, e.g. {"*":{"read":true},"role:admin":{"write":true}}
+func (acl ACL) MarshalJSON() ([]byte, error) {
+	if acl.content == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(acl.content)
+}
+
+// UnmarshalJSON decodes the ACL in the form of LeanCloud
+func (acl *ACL) UnmarshalJSON(data []byte) error {
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	decodedACL, err := decodeACL(fields)
+	if err != nil {
+		return err
+	}
+	acl.content = decodedACL.content
+
+	return nil
+}
+
+// String returns the ACL in JSON
+func (acl *ACL) String() string {
+	data, _ := acl.MarshalJSON()
+	return string(data)
 }
 
 func (acl *ACL) set(key, perm string, allowed bool) {
+	if acl.content == nil {
+		acl.content = make(map[string]map[string]bool)
+	}
+
+	if !allowed {
+		delete(acl.content[key], perm)
+		if len(acl.content[key]) == 0 {
+			delete(acl.content, key)
+		}
+		return
+	}
+
+	if acl.content[key] == nil {
+		acl.content[key] = make(map[string]bool)
+	}
 	acl.content[key][perm] = allowed
 }
 
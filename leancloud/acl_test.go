@@ -0,0 +1,108 @@
+package leancloud
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestACLAccess(t *testing.T) {
+	acl := NewACL()
+	acl.SetPublicReadAccess(true)
+	acl.SetWriteAccessByID("user1", true)
+	acl.SetRoleWriteAccessByName("admin", true)
+
+	if !acl.GetPublicReadAccess() || acl.GetPublicWriteAccess() {
+		t.Fatal("dismatch public access")
+	}
+	if !acl.GetWriteAccessByID("user1") || acl.GetReadAccessByID("user1") {
+		t.Fatal("dismatch user access")
+	}
+	if !acl.GetRoleWriteAccess(&Role{Name: "admin"}) {
+		t.Fatal("dismatch role access")
+	}
+
+	acl.SetRoleWriteAccessByName("admin", false)
+	if acl.GetRoleWriteAccessByName("admin") {
+		t.Fatal("role access not revoked")
+	}
+
+	var zero ACL
+	zero.SetPublicReadAccess(true)
+	if !zero.GetPublicReadAccess() {
+		t.Fatal("dismatch access of zero ACL")
+	}
+}
+
+func TestACLEvaluate(t *testing.T) {
+	acl := NewACL()
+	acl.SetReadAccessByID("user1", true)
+	acl.SetRoleWriteAccessByName("admin", true)
+
+	user1 := &User{Object: Object{ID: "user1"}}
+	user2 := &User{Object: Object{ID: "user2"}}
+	admin := &Role{Name: "admin"}
+
+	if !acl.CanRead(user1) || acl.CanWrite(user1) {
+		t.Fatal("dismatch access of user1")
+	}
+	if acl.CanRead(user2) || !acl.CanWrite(user2, admin) {
+		t.Fatal("dismatch access of user2")
+	}
+	if acl.CanRead(nil) {
+		t.Fatal("dismatch access of anonymous")
+	}
+
+	acl.SetPublicReadAccess(true)
+	if !acl.CanRead(nil) {
+		t.Fatal("dismatch public access")
+	}
+}
+
+func TestACLJSON(t *testing.T) {
+	acl := NewACL()
+	acl.SetPublicReadAccess(true)
+	acl.SetRoleWriteAccessByName("admin", true)
+
+	data, err := json.Marshal(acl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"*":{"read":true},"role:admin":{"write":true}}` {
+		t.Fatalf("unexpected JSON %s", string(data))
+	}
+
+	decodedACL := new(ACL)
+	if err := json.Unmarshal(data, decodedACL); err != nil {
+		t.Fatal(err)
+	}
+	if !decodedACL.GetPublicReadAccess() || !decodedACL.GetRoleWriteAccessByName("admin") {
+		t.Fatal("dismatch decoded ACL")
+	}
+
+	encodedACL, err := json.Marshal(encode(acl, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encodedACL) != string(data) {
+		t.Fatalf("unexpected encoded ACL %s", string(encodedACL))
+	}
+}
+
+func TestDecodeObjectACL(t *testing.T) {
+	object, err := decodeObject(map[string]interface{}{
+		"objectId":  "object1",
+		"createdAt": "2020-01-01T00:00:00.000Z",
+		"updatedAt": "2020-01-01T00:00:00.000Z",
+		"ACL": map[string]interface{}{
+			"user1": map[string]interface{}{"read": true, "write": true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acl := object.ACL()
+	if acl == nil || !acl.GetWriteAccessByID("user1") {
+		t.Fatal("ACL not decoded")
+	}
+}
@@ -0,0 +1,544 @@
+package leancloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	qiniuBlockSize           = 4 << 20
+	s3MinPartSize            = 5 << 20
+	defaultChunkSize         = qiniuBlockSize
+	defaultChunkRetries      = 3
+	defaultChunkRetryBackoff = time.Second
+)
+
+// UploadState records the progress of a chunked upload, it could be serialized to JSON
+// and passed back through ChunkedUploadOptions to resume an interrupted upload
+type UploadState struct {
+	Provider  string       `json:"provider"`
+	Token     string       `json:"token"`
+	UploadURL string       `json:"uploadUrl"`
+	ObjectID  string       `json:"objectId"`
+	CreatedAt time.Time    `json:"createdAt"`
+	Key       string       `json:"key"`
+	URL       string       `json:"url"`
+	Bucket    string       `json:"bucket"`
+	Size      int64        `json:"size"`
+	ChunkSize int64        `json:"chunkSize"`
+	UploadID  string       `json:"uploadId,omitempty"`
+	Parts     []UploadPart `json:"parts"`
+}
+
+// UploadPart is a chunk which has been uploaded
+type UploadPart struct {
+	Number int    `json:"number"`
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+	Tag    string `json:"tag"`
+}
+
+// ChunkedUploadOptions controls how the file is split and retried, and where to resume from
+type ChunkedUploadOptions struct {
+	// UploadOptions reports progress after each chunk uploaded, a cancelled upload is aborted and could not be resumed
+	UploadOptions
+
+	// ChunkSize is size of each chunk in bytes, Qiniu limits it to 4 MiB and S3 requires at least 5 MiB
+	ChunkSize int64
+
+	// Retries is how many times a failed chunk is retried before the upload gives up
+	Retries int
+
+	// State resumes the upload if set, otherwise it will be set to the state of the new upload
+	State *UploadState
+
+	// OnChunkUploaded is called with the latest state after each chunk uploaded, e.g. to persist it
+	OnChunkUploaded func(*UploadState)
+}
+
+type s3InitiateMultipartUploadResult struct {
+	UploadID string `xml:"UploadId"`
+}
+
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type s3CompleteMultipartUpload struct {
+	XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+	Parts   []s3CompletedPart `xml:"Part"`
+}
+
+// UploadChunked transfer the file to cloud storage in chunks and create a File object in _File class.
+// If the upload fails, options.State could be used to resume it later or to abort it by AbortChunkedUpload.
+func (ref *Files) UploadChunked(file *File, reader io.ReadSeeker, options *ChunkedUploadOptions, authOptions ...AuthOption) error {
+	if options == nil {
+		options = new(ChunkedUploadOptions)
+	}
+
+	if options.Retries == 0 {
+		options.Retries = defaultChunkRetries
+	}
+
+	ctx := options.UploadOptions.context()
+
+	size, err := getSeekerSize(reader)
+	if err != nil {
+		return fmt.Errorf("unexpected error when get length of file: %v", err)
+	}
+
+	if size == 0 && options.State == nil {
+		// empty files have no chunk, which multipart uploads of S3 could not complete
+		return ref.UploadFromReader(file, reader, size, &options.UploadOptions, authOptions...)
+	}
+
+	state := options.State
+	if state == nil {
+		if err := ref.prepareMetadata(file, size, authOptions...); err != nil {
+			return err
+		}
+
+		token, uploadURL, err := file.fetchToken(ref.c, authOptions...)
+		if err != nil {
+			return err
+		}
+
+		state = &UploadState{
+			Provider:  file.Provider,
+			Token:     token,
+			UploadURL: uploadURL,
+			ObjectID:  file.ID,
+			CreatedAt: file.CreatedAt,
+			Key:       file.Key,
+			URL:       file.URL,
+			Bucket:    file.Bucket,
+			Size:      size,
+			ChunkSize: options.ChunkSize,
+		}
+		options.State = state
+	} else {
+		if state.Size != size {
+			return fmt.Errorf("unable to resume upload: size of file changed from %d to %d", state.Size, size)
+		}
+		file.ID = state.ObjectID
+		file.CreatedAt = state.CreatedAt
+		file.Key = state.Key
+		file.URL = state.URL
+		file.Bucket = state.Bucket
+		file.Provider = state.Provider
+		file.Size = size
+	}
+
+	var qiniuHosts *qiniuUploadHosts
+	switch state.Provider {
+	case "qiniu":
+		qiniuHosts = newQiniuUploadHosts(state.Token, state.UploadURL, state.Bucket)
+		if state.ChunkSize <= 0 || state.ChunkSize > qiniuBlockSize {
+			state.ChunkSize = defaultChunkSize
+		}
+	case "s3":
+		if ref.c.s3Credentials == nil {
+			return fmt.Errorf("unable to upload file to AWS S3 in chunks: S3Credentials is not set")
+		}
+		if state.ChunkSize < s3MinPartSize {
+			state.ChunkSize = s3MinPartSize
+		}
+		if state.UploadID == "" {
+			uploadID, err := file.initiateS3MultipartUpload(ctx, ref.c.s3Credentials, state.UploadURL)
+			if err != nil {
+				return err
+			}
+			state.UploadID = uploadID
+		}
+	default:
+		return fmt.Errorf("chunked upload is not supported by provider %s", state.Provider)
+	}
+
+	// a cancelled upload is aborted, including while waiting to retry a chunk
+	abort := func() error {
+		if err := ref.AbortChunkedUpload(state, authOptions...); err != nil {
+			return err
+		}
+		return ctx.Err()
+	}
+
+	for offset := state.uploadedSize(); offset < state.Size; offset = state.uploadedSize() {
+		if ctx.Err() != nil {
+			return abort()
+		}
+
+		end := offset + state.ChunkSize
+		if state.Provider == "qiniu" {
+			if blockEnd := (offset/qiniuBlockSize + 1) * qiniuBlockSize; end > blockEnd {
+				end = blockEnd
+			}
+		}
+		if end > state.Size {
+			end = state.Size
+		}
+
+		chunk := make([]byte, end-offset)
+		if _, err := reader.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("unexpected error when read chunk of file: %v", err)
+		}
+		if _, err := io.ReadFull(reader, chunk); err != nil {
+			return fmt.Errorf("unexpected error when read chunk of file: %v", err)
+		}
+
+		part := UploadPart{
+			Number: len(state.Parts) + 1,
+			Offset: offset,
+			Size:   end - offset,
+		}
+
+		for retry := 0; ; retry++ {
+			switch state.Provider {
+			case "qiniu":
+				part.Tag, err = file.uploadQiniuChunk(ctx, qiniuHosts, state, offset, chunk)
+			case "s3":
+				part.Tag, err = file.uploadS3Part(ctx, ref.c.s3Credentials, state, part.Number, chunk)
+			}
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return abort()
+			}
+			if retry >= options.Retries {
+				return err
+			}
+
+			backoff := time.NewTimer(defaultChunkRetryBackoff * time.Duration(retry+1))
+			select {
+			case <-ctx.Done():
+				backoff.Stop()
+				return abort()
+			case <-backoff.C:
+			}
+		}
+
+		state.Parts = append(state.Parts, part)
+		options.UploadOptions.progress(state.uploadedSize(), state.Size)
+		if options.OnChunkUploaded != nil {
+			options.OnChunkUploaded(state)
+		}
+	}
+
+	switch state.Provider {
+	case "qiniu":
+		if err := file.completeQiniuChunks(ctx, qiniuHosts, state); err != nil {
+			return err
+		}
+	case "s3":
+		if err := file.completeS3MultipartUpload(ctx, ref.c.s3Credentials, state); err != nil {
+			return err
+		}
+	}
+
+	if err := file.fileCallback(true, state.Token, ref.c, authOptions...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AbortChunkedUpload gives up the interrupted upload and notify LeanCloud the failure
+func (ref *Files) AbortChunkedUpload(state *UploadState, authOptions ...AuthOption) error {
+	file := &File{
+		Key:      state.Key,
+		Provider: state.Provider,
+	}
+
+	if state.Provider == "s3" && state.UploadID != "" && ref.c.s3Credentials != nil {
+		if err := file.abortS3MultipartUpload(context.Background(), ref.c.s3Credentials, state); err != nil {
+			return err
+		}
+	}
+
+	return file.fileCallback(false, state.Token, ref.c, authOptions...)
+}
+
+func (state *UploadState) uploadedSize() int64 {
+	if len(state.Parts) == 0 {
+		return 0
+	}
+
+	last := state.Parts[len(state.Parts)-1]
+	return last.Offset + last.Size
+}
+
+func (file *File) uploadQiniuChunk(ctx context.Context, hosts *qiniuUploadHosts, state *UploadState, offset int64, chunk []byte) (string, error) {
+	var path string
+	if offset%qiniuBlockSize == 0 {
+		blockSize := int64(qiniuBlockSize)
+		if state.Size-offset < blockSize {
+			blockSize = state.Size - offset
+		}
+		path = fmt.Sprint("mkblk/", blockSize)
+	} else {
+		previous := state.Parts[len(state.Parts)-1]
+		path = fmt.Sprint("bput/", previous.Tag, "/", offset%qiniuBlockSize)
+	}
+
+	content, err := file.requestQiniu(ctx, hosts, state.Token, path, "application/octet-stream", chunk)
+	if err != nil {
+		return "", err
+	}
+
+	respJSON := make(map[string]interface{})
+	if err := json.Unmarshal(content, &respJSON); err != nil {
+		return "", fmt.Errorf("unexpected error when upload file to Qiniu: %v", err)
+	}
+
+	blockContext, ok := respJSON["ctx"].(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected error when upload file to Qiniu: %v", string(content))
+	}
+
+	return blockContext, nil
+}
+
+func (file *File) completeQiniuChunks(ctx context.Context, hosts *qiniuUploadHosts, state *UploadState) error {
+	var contexts []string
+	for _, part := range state.Parts {
+		end := part.Offset + part.Size
+		if end%qiniuBlockSize == 0 || end == state.Size {
+			contexts = append(contexts, part.Tag)
+		}
+	}
+
+	path := fmt.Sprint("mkfile/", state.Size, "/key/", base64.URLEncoding.EncodeToString([]byte(state.Key)))
+	if file.MIME != "" {
+		path = fmt.Sprint(path, "/mimeType/", base64.URLEncoding.EncodeToString([]byte(file.MIME)))
+	}
+
+	if _, err := file.requestQiniu(ctx, hosts, state.Token, path, "text/plain", []byte(strings.Join(contexts, ","))); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (file *File) requestQiniu(ctx context.Context, hosts *qiniuUploadHosts, token, path, contentType string, body []byte) ([]byte, error) {
+	var err error
+	for i := 0; ; i++ {
+		host, ok := hosts.get(ctx, i)
+		if !ok {
+			return nil, err
+		}
+		var content []byte
+		content, err = file.requestQiniuHost(ctx, host, token, path, contentType, body)
+		if err == nil || !isConnectionError(err) {
+			return content, err
+		}
+	}
+}
+
+func (file *File) requestQiniuHost(ctx context.Context, host, token, path, contentType string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprint(host, "/", path), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprint("UpToken ", token))
+	req.Header.Set("Content-Type", contentType)
+	req.ContentLength = int64(len(body))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error when upload file to Qiniu: %w", err)
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error when upload file to Qiniu: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected error when upload file to Qiniu: %v", string(content))
+	}
+
+	return content, nil
+}
+
+// uploadS3Multipart streams the reader of unknown size to AWS S3 in parts of the minimum size
+func (file *File) uploadS3Multipart(ctx context.Context, credentials *S3Credentials, uploadURL string, reader io.Reader) error {
+	uploadID, err := file.initiateS3MultipartUpload(ctx, credentials, uploadURL)
+	if err != nil {
+		return err
+	}
+
+	state := &UploadState{
+		UploadURL: uploadURL,
+		UploadID:  uploadID,
+		ChunkSize: s3MinPartSize,
+	}
+
+	chunk := make([]byte, state.ChunkSize)
+	for {
+		n, err := io.ReadFull(reader, chunk)
+		if err == io.EOF && len(state.Parts) > 0 {
+			break
+		}
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return file.abortS3MultipartUploadWithError(credentials, state, err)
+		}
+
+		part := UploadPart{
+			Number: len(state.Parts) + 1,
+			Offset: state.uploadedSize(),
+			Size:   int64(n),
+		}
+		part.Tag, err = file.uploadS3Part(ctx, credentials, state, part.Number, chunk[:n])
+		if err != nil {
+			return file.abortS3MultipartUploadWithError(credentials, state, err)
+		}
+		state.Parts = append(state.Parts, part)
+
+		if int64(n) < state.ChunkSize {
+			break
+		}
+	}
+
+	return file.completeS3MultipartUpload(ctx, credentials, state)
+}
+
+func (file *File) initiateS3MultipartUpload(ctx context.Context, credentials *S3Credentials, uploadURL string) (string, error) {
+	resp, content, err := file.requestS3(ctx, credentials, "POST", uploadURL, url.Values{"uploads": {""}}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	result := new(s3InitiateMultipartUploadResult)
+	if err := xml.Unmarshal(content, result); err != nil {
+		return "", fmt.Errorf("unexpected error when upload file to AWS S3: %v", err)
+	}
+	if result.UploadID == "" {
+		return "", fmt.Errorf("unexpected error when upload file to AWS S3: no UploadId in response (%d)", resp.StatusCode)
+	}
+
+	return result.UploadID, nil
+}
+
+func (file *File) uploadS3Part(ctx context.Context, credentials *S3Credentials, state *UploadState, number int, chunk []byte) (string, error) {
+	query := url.Values{
+		"partNumber": {fmt.Sprint(number)},
+		"uploadId":   {state.UploadID},
+	}
+
+	resp, _, err := file.requestS3(ctx, credentials, "PUT", state.UploadURL, query, chunk)
+	if err != nil {
+		return "", err
+	}
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return "", fmt.Errorf("unexpected error when upload file to AWS S3: no ETag of part %d in response", number)
+	}
+
+	return etag, nil
+}
+
+func (file *File) completeS3MultipartUpload(ctx context.Context, credentials *S3Credentials, state *UploadState) error {
+	complete := new(s3CompleteMultipartUpload)
+	for _, part := range state.Parts {
+		complete.Parts = append(complete.Parts, s3CompletedPart{
+			PartNumber: part.Number,
+			ETag:       part.Tag,
+		})
+	}
+
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		return fmt.Errorf("unexpected error when upload file to AWS S3: %v", err)
+	}
+
+	if _, _, err := file.requestS3(ctx, credentials, "POST", state.UploadURL, url.Values{"uploadId": {state.UploadID}}, body); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (file *File) abortS3MultipartUpload(ctx context.Context, credentials *S3Credentials, state *UploadState) error {
+	if _, _, err := file.requestS3(ctx, credentials, "DELETE", state.UploadURL, url.Values{"uploadId": {state.UploadID}}, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// abortS3MultipartUploadWithError aborts the multipart upload failed with err, and returns err along with the error of aborting if any
+func (file *File) abortS3MultipartUploadWithError(credentials *S3Credentials, state *UploadState, err error) error {
+	if abortErr := file.abortS3MultipartUpload(context.Background(), credentials, state); abortErr != nil {
+		return fmt.Errorf("%w (and failed to abort the multipart upload: %v)", err, abortErr)
+	}
+
+	return err
+}
+
+func (file *File) requestS3(ctx context.Context, credentials *S3Credentials, method, uploadURL string, query url.Values, body []byte) (*http.Response, []byte, error) {
+	objectURL, err := url.Parse(uploadURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unexpected error when upload file to AWS S3: %v", err)
+	}
+	objectURL.RawQuery = s3MergeQuery(objectURL.Query(), query)
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.ContentLength = int64(len(body))
+	credentials.sign(req, s3Hash(body), time.Now())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unexpected error when upload file to AWS S3: %v", err)
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unexpected error when upload file to AWS S3: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("unexpected error when upload file to AWS S3: %v", string(content))
+	}
+
+	return resp, content, nil
+}
+
+// s3MergeQuery adds query to parameters of the upload_url. Parameters of a presigned URL are dropped,
+// since the request is signed with S3Credentials in headers instead, and S3 rejects both at once
+func s3MergeQuery(urlQuery, query url.Values) string {
+	merged := url.Values{}
+	for key, values := range urlQuery {
+		if !s3PresignParams[key] {
+			merged[key] = values
+		}
+	}
+	for key, values := range query {
+		merged[key] = values
+	}
+
+	pairs := strings.Split(merged.Encode(), "&")
+	for i, pair := range pairs {
+		// sub-resources like ?uploads carry no value
+		if pair == "uploads=" {
+			pairs[i] = "uploads"
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
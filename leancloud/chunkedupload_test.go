@@ -0,0 +1,190 @@
+package leancloud
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUploadChunkedQiniu(t *testing.T) {
+	// 1.5 blocks, in chunks of 1 MiB
+	size := qiniuBlockSize + qiniuBlockSize/2
+	content := bytes.Repeat([]byte("0123456789abcdef"), size/16)
+
+	var paths []string
+	var received []byte
+	var mkfile string
+	// upload_url of Qiniu is the host, to which paths of chunk APIs are appended
+	server := newUploadServer(t, "qiniu", "/storage", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "UpToken token1" {
+			t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		path := strings.TrimPrefix(r.URL.Path, "/storage/")
+		paths = append(paths, path)
+		if strings.HasPrefix(path, "mkfile/") {
+			mkfile = string(body)
+			w.Write([]byte(`{"key":"key/test.bin"}`))
+			return
+		}
+		received = append(received, body...)
+		fmt.Fprintf(w, `{"ctx":"ctx%d"}`, len(paths))
+	})
+	defer server.Close()
+
+	client := server.client(nil)
+	var progress []int64
+	options := &ChunkedUploadOptions{
+		UploadOptions: UploadOptions{
+			OnProgress: func(sent, total int64) {
+				progress = append(progress, sent)
+			},
+		},
+		ChunkSize: 1 << 20,
+	}
+	file := &File{Name: "test.bin"}
+	if err := client.Files.UploadChunked(file, bytes.NewReader(content), options); err != nil {
+		t.Fatal(err)
+	}
+
+	wantPaths := []string{
+		"mkblk/4194304",
+		"bput/ctx1/1048576",
+		"bput/ctx2/2097152",
+		"bput/ctx3/3145728",
+		"mkblk/2097152",
+		"bput/ctx5/1048576",
+		fmt.Sprint("mkfile/", size, "/key/", base64.URLEncoding.EncodeToString([]byte("key/test.bin"))),
+	}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Fatalf("unexpected requests %v, want %v", paths, wantPaths)
+	}
+	if !bytes.Equal(received, content) {
+		t.Fatal("dismatch content")
+	}
+	if mkfile != "ctx4,ctx6" {
+		t.Fatalf("unexpected contexts of blocks %s", mkfile)
+	}
+	if len(options.State.Parts) != 6 || options.State.Parts[5].Size != 1<<20 || options.State.Parts[5].Offset != int64(size)-1<<20 {
+		t.Fatalf("unexpected parts %v", options.State.Parts)
+	}
+	if len(progress) != 6 || progress[5] != int64(size) {
+		t.Fatalf("unexpected progress %v", progress)
+	}
+	if !reflect.DeepEqual(server.callbacks, []bool{true}) || file.ID != "file1" {
+		t.Fatalf("unexpected callbacks %v", server.callbacks)
+	}
+}
+
+func TestUploadChunkedS3(t *testing.T) {
+	size := s3MinPartSize + 1
+	content := bytes.Repeat([]byte{'x'}, size)
+	content[size-1] = 'y'
+
+	parts := make(map[string][]byte)
+	var completed s3CompleteMultipartUpload
+	// upload_url of S3 is presigned for a single PUT, its own parameters should be kept
+	server := newUploadServer(t, "s3", "/storage/key/test.bin?x-id=PutObject&X-Amz-Signature=presigned", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") {
+			t.Errorf("request not signed")
+		}
+		if r.URL.Path != "/storage/key/test.bin" || query.Get("x-id") != "PutObject" || query.Get("X-Amz-Signature") != "" {
+			t.Errorf("unexpected URL %s", r.URL)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		switch {
+		case r.Method == "POST" && r.URL.RawQuery == "uploads&x-id=PutObject":
+			w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>upload1</UploadId></InitiateMultipartUploadResult>`))
+		case r.Method == "PUT" && query.Get("uploadId") == "upload1":
+			parts[query.Get("partNumber")] = body
+			w.Header().Set("ETag", fmt.Sprintf(`"etag%s"`, query.Get("partNumber")))
+		case r.Method == "POST" && query.Get("uploadId") == "upload1":
+			if err := xml.Unmarshal(body, &completed); err != nil {
+				t.Error(err)
+			}
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	defer server.Close()
+
+	client := server.client(&S3Credentials{AccessKeyID: "access", SecretAccessKey: "secret"})
+	options := &ChunkedUploadOptions{}
+	if err := client.Files.UploadChunked(&File{Name: "test.bin"}, bytes.NewReader(content), options); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(parts) != 2 || len(parts["1"]) != s3MinPartSize || !bytes.Equal(parts["2"], []byte{'y'}) {
+		t.Fatalf("unexpected parts: %d", len(parts))
+	}
+	if !reflect.DeepEqual(completed.Parts, []s3CompletedPart{{1, `"etag1"`}, {2, `"etag2"`}}) {
+		t.Fatalf("unexpected completed parts %v", completed.Parts)
+	}
+	if options.State.UploadID != "upload1" || options.State.Parts[1].Tag != `"etag2"` {
+		t.Fatalf("unexpected state %v", options.State)
+	}
+	if !reflect.DeepEqual(server.callbacks, []bool{true}) {
+		t.Fatalf("unexpected callbacks %v", server.callbacks)
+	}
+}
+
+func TestUploadChunkedEmpty(t *testing.T) {
+	var requests []string
+	server := newUploadServer(t, "s3", "/storage/key/test.bin", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprint(r.Method, " ", r.URL.RawQuery, " ", len(body)))
+	})
+	defer server.Close()
+
+	client := server.client(&S3Credentials{AccessKeyID: "access", SecretAccessKey: "secret"})
+	if err := client.Files.UploadChunked(&File{Name: "test.bin"}, bytes.NewReader(nil), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// empty files are uploaded in a single PUT rather than a multipart upload without parts
+	if !reflect.DeepEqual(requests, []string{"PUT  0"}) {
+		t.Fatalf("unexpected requests %v", requests)
+	}
+	if !reflect.DeepEqual(server.callbacks, []bool{true}) {
+		t.Fatalf("unexpected callbacks %v", server.callbacks)
+	}
+}
+
+func TestAbortChunkedUpload(t *testing.T) {
+	var aborted []string
+	server := newUploadServer(t, "s3", "/storage/key/test.bin", func(w http.ResponseWriter, r *http.Request) {
+		aborted = append(aborted, fmt.Sprint(r.Method, " ", r.URL.Query().Get("uploadId")))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer server.Close()
+
+	client := server.client(&S3Credentials{AccessKeyID: "access", SecretAccessKey: "secret"})
+	state := &UploadState{
+		Provider:  "s3",
+		Token:     "token1",
+		UploadURL: server.uploadURL,
+		UploadID:  "upload1",
+	}
+	if err := client.Files.AbortChunkedUpload(state); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(aborted, []string{"DELETE upload1"}) || !reflect.DeepEqual(server.callbacks, []bool{false}) {
+		t.Fatalf("upload not aborted: %v %v", aborted, server.callbacks)
+	}
+
+	state.Provider = "qiniu"
+	if err := client.Files.AbortChunkedUpload(state); err != nil {
+		t.Fatal(err)
+	}
+	if len(aborted) != 1 || !reflect.DeepEqual(server.callbacks, []bool{false, false}) {
+		t.Fatalf("unexpected abort of Qiniu upload: %v %v", aborted, server.callbacks)
+	}
+}
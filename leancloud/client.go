@@ -10,14 +10,22 @@ import (
 const Version = "0.1.0"
 
 type Client struct {
-	serverURL     string
-	appID         string
-	appKey        string
-	masterKey     string
-	requestLogger *log.Logger
-	Users         Users
-	Files         Files
-	Roles         Roles
+	serverURL        string
+	appID            string
+	appKey           string
+	masterKey        string
+	requestLogger    *log.Logger
+	sessionCache     SessionCache
+	authOptions      []AuthOption
+	s3Credentials    *S3Credentials
+	qiniuCredentials *QiniuCredentials
+	cosCredentials   *COSCredentials
+	currentUser      *User
+	Users            Users
+	Files            Files
+	Roles            Roles
+	Statuses         Statuses
+	Schemas          Schemas
 }
 
 type ClientOptions struct {
@@ -25,15 +33,37 @@ type ClientOptions struct {
 	AppKey    string
 	MasterKey string
 	ServerURL string
+
+	// SessionCache caches users resolved from session tokens in LeanEngine,
+	// an in-memory LRU cache is used if not set
+	SessionCache SessionCache
+
+	// S3Credentials is required by multipart uploads to AWS S3 and presigned URLs of private buckets
+	S3Credentials *S3Credentials
+
+	// QiniuCredentials is required by signed URLs of private Qiniu buckets
+	QiniuCredentials *QiniuCredentials
+
+	// COSCredentials is required by signed URLs of private Tencent Cloud COS buckets
+	COSCredentials *COSCredentials
 }
 
 // NewClient constructs a client from parameters
 func NewClient(options *ClientOptions) *Client {
 	client := &Client{
-		appID:     options.AppID,
-		appKey:    options.AppKey,
-		masterKey: options.MasterKey,
-		serverURL: options.ServerURL,
+		appID:            options.AppID,
+		appKey:           options.AppKey,
+		masterKey:        options.MasterKey,
+		serverURL:        options.ServerURL,
+		s3Credentials:    options.S3Credentials,
+		qiniuCredentials: options.QiniuCredentials,
+		cosCredentials:   options.COSCredentials,
+	}
+
+	if options.SessionCache != nil {
+		client.sessionCache = options.SessionCache
+	} else {
+		client.sessionCache = NewLRUSessionCache(defaultSessionCacheSize, defaultSessionCacheTTL)
 	}
 
 	if !strings.HasSuffix(options.AppID, "MdYXbMMI") {
@@ -51,6 +81,8 @@ func NewClient(options *ClientOptions) *Client {
 	client.Users.c = client
 	client.Files.c = client
 	client.Roles.c = client
+	client.Statuses.c = client
+	client.Schemas.c = client
 	return client
 }
 
@@ -82,3 +114,62 @@ func (client *Client) File(id string) *FileRef {
 		ID:    id,
 	}
 }
+
+// WithUser constructs a client scoped to the user, requests sent by it carry the user's session
+func (client *Client) WithUser(user *User) *Client {
+	scoped := client.scope(UseUser(user))
+	scoped.currentUser = user
+	return scoped
+}
+
+// WithSessionToken constructs a client scoped to the session, requests sent by it carry the sessionToken
+func (client *Client) WithSessionToken(sessionToken string) *Client {
+	scoped := client.scope(UseSessionToken(sessionToken))
+	scoped.currentUser = nil
+	return scoped
+}
+
+// WithMasterKey constructs a client whose requests are all sent with the master key
+func (client *Client) WithMasterKey() *Client {
+	return client.scope(UseMasterKey(true))
+}
+
+// CurrentUser returns the user which the client is scoped to, or nil if it is not scoped to any session
+func (client *Client) CurrentUser() (*User, error) {
+	if client.currentUser != nil {
+		return client.currentUser, nil
+	}
+
+	sessionToken := client.getRequestOptions().Headers["X-LC-Session"]
+	if sessionToken == "" {
+		return nil, nil
+	}
+
+	return client.Users.becomeWithCache(sessionToken)
+}
+
+// S3Credentials returns the credentials of AWS S3 set in ClientOptions, or nil if not set
+func (client *Client) S3Credentials() *S3Credentials {
+	return client.s3Credentials
+}
+
+// QiniuCredentials returns the credentials of Qiniu set in ClientOptions, or nil if not set
+func (client *Client) QiniuCredentials() *QiniuCredentials {
+	return client.qiniuCredentials
+}
+
+// COSCredentials returns the credentials of Tencent Cloud COS set in ClientOptions, or nil if not set
+func (client *Client) COSCredentials() *COSCredentials {
+	return client.cosCredentials
+}
+
+func (client *Client) scope(authOptions ...AuthOption) *Client {
+	scoped := *client
+	scoped.authOptions = append(append([]AuthOption{}, client.authOptions...), authOptions...)
+	scoped.Users.c = &scoped
+	scoped.Files.c = &scoped
+	scoped.Roles.c = &scoped
+	scoped.Statuses.c = &scoped
+	scoped.Schemas.c = &scoped
+	return &scoped
+}
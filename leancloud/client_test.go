@@ -121,3 +121,49 @@ func TestClientObject(t *testing.T) {
 		t.Fatal(errors.New("ID unmatch"))
 	}
 }
+
+func TestClientScope(t *testing.T) {
+	client := &Client{appID: "appID", appKey: "appKey", masterKey: "masterKey"}
+
+	t.Run("WithSessionToken", func(t *testing.T) {
+		scoped := client.WithSessionToken("sessionToken")
+		if scoped.Users.c != scoped || scoped.Files.c != scoped || scoped.Roles.c != scoped {
+			t.Fatal(errors.New("client unmatch"))
+		}
+		if scoped.Class("class").c != scoped {
+			t.Fatal(errors.New("client unmatch"))
+		}
+		if scoped.getRequestOptions().Headers["X-LC-Session"] != "sessionToken" {
+			t.Fatal(errors.New("sessionToken unmatch"))
+		}
+		if client.getRequestOptions().Headers["X-LC-Session"] != "" {
+			t.Fatal(errors.New("parent client should not be scoped"))
+		}
+	})
+
+	t.Run("WithUser", func(t *testing.T) {
+		user := &User{SessionToken: "sessionToken"}
+		scoped := client.WithUser(user)
+		if scoped.getRequestOptions().Headers["X-LC-Session"] != "sessionToken" {
+			t.Fatal(errors.New("sessionToken unmatch"))
+		}
+		currentUser, err := scoped.CurrentUser()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if currentUser != user {
+			t.Fatal(errors.New("current user unmatch"))
+		}
+	})
+
+	t.Run("WithMasterKey", func(t *testing.T) {
+		scoped := client.WithSessionToken("sessionToken").WithMasterKey()
+		options := scoped.getRequestOptions()
+		if options.Headers["X-LC-Key"] != "masterKey,master" {
+			t.Fatal(errors.New("master key unmatch"))
+		}
+		if options.Headers["X-LC-Session"] != "sessionToken" {
+			t.Fatal(errors.New("sessionToken unmatch"))
+		}
+	})
+}
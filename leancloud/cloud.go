@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sync"
 
 	"github.com/levigross/grequests"
 )
@@ -103,11 +104,22 @@ type functionType struct {
 
 var client *Client
 
+var clientOnce sync.Once
+
 var functions map[string]*functionType
 
 func init() {
 	functions = make(map[string]*functionType)
-	client = NewEnvClient()
+}
+
+// engineClient returns the client constructed from environment variables of LeanEngine on first use,
+// so that the package could be imported without them, e.g. by lcgen
+func engineClient() *Client {
+	clientOnce.Do(func() {
+		client = NewEnvClient()
+	})
+
+	return client
 }
 
 // Define declares a Cloud Function with name & options of definition
@@ -155,6 +167,7 @@ func Run(name string, object interface{}, runOptions ...RunOption) (interface{},
 		var err error
 		var resp *grequests.Response
 		path := fmt.Sprint("/1.1/functions/", name)
+		client := engineClient()
 		reqOption := client.getRequestOptions()
 		reqOption.JSON = object
 		if sessionToken != "" {
@@ -189,7 +202,7 @@ func Run(name string, object interface{}, runOptions ...RunOption) (interface{},
 
 	if sessionToken != "" {
 		request.SessionToken = sessionToken
-		user, err := client.Users.Become(sessionToken)
+		user, err := engineClient().Users.becomeWithCache(sessionToken)
 		if err != nil {
 			return nil, err
 		}
@@ -230,6 +243,7 @@ func RPC(name string, params interface{}, results interface{}, runOptions ...Run
 		var err error
 		var resp *grequests.Response
 		path := fmt.Sprint("/1.1/call/", name)
+		client := engineClient()
 		reqOption := client.getRequestOptions()
 		reqOption.JSON = encode(params, true)
 		if sessionToken != "" {
@@ -274,7 +288,7 @@ func RPC(name string, params interface{}, results interface{}, runOptions ...Run
 
 	if sessionToken != "" {
 		request.SessionToken = sessionToken
-		user, err := client.Users.Become(sessionToken)
+		user, err := engineClient().Users.becomeWithCache(sessionToken)
 		if err != nil {
 			return err
 		}
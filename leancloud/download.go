@@ -0,0 +1,231 @@
+package leancloud
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// DownloadOptions controls the range and cancellation of a download
+type DownloadOptions struct {
+	Context context.Context
+
+	// Offset is the first byte to download, e.g. bytes already saved by an interrupted download
+	Offset int64
+
+	// Length is the number of bytes to download, 0 means to the end of the file
+	Length int64
+}
+
+func (options *DownloadOptions) context() context.Context {
+	if options == nil || options.Context == nil {
+		return context.Background()
+	}
+
+	return options.Context
+}
+
+// downloadVerifier checks the downloaded content against size and MD5 checksum recorded in metadata
+type downloadVerifier struct {
+	hash             hash.Hash
+	size             int64
+	expectedSize     int64
+	expectedChecksum string
+}
+
+func newDownloadVerifier(file *File) *downloadVerifier {
+	verifier := &downloadVerifier{
+		hash:         md5.New(),
+		expectedSize: -1,
+	}
+
+	switch size := file.Meatadata["size"].(type) {
+	case float64:
+		verifier.expectedSize = int64(size)
+	case int64:
+		verifier.expectedSize = size
+	case int:
+		verifier.expectedSize = int64(size)
+	}
+
+	if checksum, ok := file.Meatadata["_checksum"].(string); ok {
+		verifier.expectedChecksum = strings.ToLower(checksum)
+	}
+
+	return verifier
+}
+
+func (verifier *downloadVerifier) Write(p []byte) (int, error) {
+	verifier.size += int64(len(p))
+	return verifier.hash.Write(p)
+}
+
+func (verifier *downloadVerifier) verify() error {
+	if verifier.expectedSize >= 0 && verifier.size != verifier.expectedSize {
+		return fmt.Errorf("unexpected size of downloaded file: want %d but %d", verifier.expectedSize, verifier.size)
+	}
+
+	if verifier.expectedChecksum != "" {
+		checksum := hex.EncodeToString(verifier.hash.Sum(nil))
+		if checksum != verifier.expectedChecksum {
+			return fmt.Errorf("unexpected checksum of downloaded file: want %s but %s", verifier.expectedChecksum, checksum)
+		}
+	}
+
+	return nil
+}
+
+// verifyingReadCloser verifies the content once the whole file has been read
+type verifyingReadCloser struct {
+	io.ReadCloser
+	verifier *downloadVerifier
+}
+
+func (reader *verifyingReadCloser) Read(p []byte) (int, error) {
+	n, err := reader.ReadCloser.Read(p)
+	reader.verifier.Write(p[:n])
+	if err == io.EOF {
+		if verifyErr := reader.verifier.verify(); verifyErr != nil {
+			return n, verifyErr
+		}
+	}
+
+	return n, err
+}
+
+// Open starts downloading the file, the content is verified against the metadata
+// when the whole file is read to the end
+func (file *File) Open(options *DownloadOptions) (io.ReadCloser, error) {
+	body, err := file.openRange(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil && (options.Offset != 0 || options.Length != 0) {
+		return body, nil
+	}
+
+	return &verifyingReadCloser{
+		ReadCloser: body,
+		verifier:   newDownloadVerifier(file),
+	}, nil
+}
+
+// Download writes the file to w and returns the number of bytes written.
+// When resuming with options.Offset, w should implement io.ReaderAt to verify the whole file,
+// otherwise only the downloaded part is written without verification.
+func (file *File) Download(w io.Writer, options *DownloadOptions) (int64, error) {
+	var verifier *downloadVerifier
+	if options == nil || options.Length == 0 {
+		verifier = newDownloadVerifier(file)
+		if options != nil && options.Offset != 0 {
+			if readerAt, ok := w.(io.ReaderAt); ok {
+				if _, err := io.Copy(verifier, io.NewSectionReader(readerAt, 0, options.Offset)); err != nil {
+					return 0, fmt.Errorf("unexpected error when read downloaded part of file: %v", err)
+				}
+			} else {
+				verifier = nil
+			}
+		}
+	}
+
+	body, err := file.openRange(options)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	var dst io.Writer = w
+	if verifier != nil {
+		dst = io.MultiWriter(w, verifier)
+	}
+
+	n, err := io.Copy(dst, body)
+	if err != nil {
+		return n, fmt.Errorf("unexpected error when download file: %v", err)
+	}
+
+	if verifier != nil {
+		if err := verifier.verify(); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+func (file *File) openRange(options *DownloadOptions) (io.ReadCloser, error) {
+	if file.URL == "" {
+		return nil, fmt.Errorf("unable to download file: url is empty")
+	}
+
+	var offset, length int64
+	if options != nil {
+		offset, length = options.Offset, options.Length
+	}
+
+	req, err := http.NewRequestWithContext(options.context(), "GET", file.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if length > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	} else if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error when download file: %v", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if offset > 0 {
+			if _, err := io.CopyN(ioutil.Discard, resp.Body, offset); err != nil {
+				resp.Body.Close()
+				return nil, fmt.Errorf("unexpected error when download file: %v", err)
+			}
+		}
+		if length > 0 {
+			return struct {
+				io.Reader
+				io.Closer
+			}{io.LimitReader(resp.Body, length), resp.Body}, nil
+		}
+		return resp.Body, nil
+	case http.StatusPartialContent:
+		return resp.Body, nil
+	default:
+		defer resp.Body.Close()
+		content, _ := ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected error when download file: %d %s", resp.StatusCode, string(content))
+	}
+}
+
+// Open fetches the referred file and starts downloading it
+func (ref *FileRef) Open(options *DownloadOptions, authOptions ...AuthOption) (io.ReadCloser, error) {
+	file := new(File)
+	if err := ref.Get(file, authOptions...); err != nil {
+		return nil, err
+	}
+
+	return file.Open(options)
+}
+
+// Download fetches the referred file and writes it to w
+func (ref *FileRef) Download(w io.Writer, options *DownloadOptions, authOptions ...AuthOption) (int64, error) {
+	file := new(File)
+	if err := ref.Get(file, authOptions...); err != nil {
+		return 0, err
+	}
+
+	return file.Download(w, options)
+}
@@ -0,0 +1,119 @@
+package leancloud
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileDownload(t *testing.T) {
+	content := []byte("temporary file's content")
+	checksum := md5.Sum(content)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/1.1/files/file1" {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"objectId":"file1","createdAt":"2020-01-01T00:00:00.000Z","updatedAt":"2020-01-01T00:00:00.000Z","url":"%s/file1.txt","metaData":{"size":%d,"_checksum":"%s"}}`,
+				server.URL, len(content), hex.EncodeToString(checksum[:]))
+			return
+		}
+		http.ServeContent(w, r, "go-sdk-file-download.txt", time.Now(), bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	file := &File{
+		URL: server.URL,
+		Meatadata: map[string]interface{}{
+			"size":      float64(len(content)),
+			"_checksum": hex.EncodeToString(checksum[:]),
+		},
+	}
+
+	t.Run("Open", func(t *testing.T) {
+		body, err := file.Open(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer body.Close()
+
+		downloaded, err := ioutil.ReadAll(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(downloaded, content) {
+			t.Fatal("dismatch content")
+		}
+	})
+
+	t.Run("FileRef", func(t *testing.T) {
+		client := NewClient(&ClientOptions{
+			AppID:     "app",
+			AppKey:    "key",
+			ServerURL: server.URL,
+		})
+
+		buf := new(bytes.Buffer)
+		n, err := client.File("file1").Download(buf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(len(content)) || !bytes.Equal(buf.Bytes(), content) {
+			t.Fatal("dismatch content")
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if _, err := file.Download(buf, &DownloadOptions{Offset: 10, Length: 4}); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != "file" {
+			t.Fatalf("dismatch content: %s", buf.String())
+		}
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		f, err := ioutil.TempFile("", "go-sdk-file-download-*.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		if _, err := f.Write(content[:10]); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Download(f, &DownloadOptions{Offset: 10}); err != nil {
+			t.Fatal(err)
+		}
+
+		downloaded, err := ioutil.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(downloaded, content) {
+			t.Fatal("dismatch content")
+		}
+	})
+
+	t.Run("Checksum", func(t *testing.T) {
+		corrupted := &File{
+			URL: server.URL,
+			Meatadata: map[string]interface{}{
+				"_checksum": "d41d8cd98f00b204e9800998ecf8427e",
+			},
+		}
+		_, err := corrupted.Download(ioutil.Discard, nil)
+		if err == nil || !strings.Contains(err.Error(), "checksum") {
+			t.Fatalf("checksum mismatch not detected: %v", err)
+		}
+	})
+}
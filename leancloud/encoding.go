@@ -113,6 +113,10 @@ func encode(object interface{}, ignoreZero bool) interface{} {
 	case *ACL:
 		return encodeACL(o)
 	default:
+		if encoded, ok := encodeMarshaler(object, ignoreZero); ok {
+			return encoded
+		}
+
 		switch reflect.ValueOf(object).Kind() {
 		case reflect.Slice, reflect.Array:
 			return encodeArray(object, ignoreZero)
@@ -161,35 +165,10 @@ func encodeObject(object interface{}, embedded bool, ignoreZero bool) map[string
 		return encodeMap(meta.fields, ignoreZero)
 	}
 
-	for i := 0; i < v.NumField(); i++ {
-		tag, option := parseTag(t.Field(i).Tag.Get("json"))
-		if option == "omitempty" && v.Field(i).IsZero() {
-			continue
-		}
-		if tag == "" {
-			tag = t.Field(i).Name
-		}
-		if v.Field(i).Kind() == reflect.Ptr || v.Field(i).Kind() == reflect.Interface {
-			if v.Field(i).IsNil() {
-				continue
-			}
-		} else {
-			if ignoreZero {
-				if v.Field(i).IsZero() {
-					continue
-				}
-			}
-		}
-
-		if isBare(v.Field(i).Interface()) && tag == "Object" {
-			continue
-		}
-
-		encoded := encode(v.Field(i).Interface(), ignoreZero)
-		if !reflect.ValueOf(encoded).IsZero() {
-			encodedObject[tag] = encoded
+	for _, field := range structFields(t) {
+		if encoded, ok := encodeStructField(v, field, ignoreZero); ok {
+			encodedObject[field.name] = encoded
 		}
-
 	}
 	return encodedObject
 }
@@ -208,11 +187,7 @@ func encodeUser(user interface{}, embedded bool, ignoreZero bool) map[string]int
 			return nil
 		}
 
-		return map[string]interface{}{
-			"__type":    "Pointer",
-			"objectId":  meta.ID,
-			"className": "_User",
-		}
+		return encodeUserPointer(meta.ID)
 	}
 	encodedUser := make(map[string]interface{})
 
@@ -220,36 +195,22 @@ func encodeUser(user interface{}, embedded bool, ignoreZero bool) map[string]int
 		return encodeMap(meta.fields, ignoreZero)
 	}
 
-	for i := 0; i < v.NumField(); i++ {
-		tag, option := parseTag(t.Field(i).Tag.Get("json"))
-		if option == "omitempty" && v.Field(i).IsZero() {
-			continue
-		}
-		if tag == "" {
-			tag = t.Field(i).Name
-		}
-		if v.Field(i).Kind() == reflect.Ptr || v.Field(i).Kind() == reflect.Interface {
-			if v.Field(i).IsNil() {
-				continue
-			}
-		} else {
-			if ignoreZero {
-				if v.Field(i).IsZero() {
-					continue
-				}
-			}
-		}
-		if isBare(v.Field(i).Interface()) && tag == "User" {
-			continue
-		}
-		encoded := encode(v.Field(i).Interface(), ignoreZero)
-		if !reflect.ValueOf(encoded).IsZero() {
-			encodedUser[tag] = encoded
+	for _, field := range structFields(t) {
+		if encoded, ok := encodeStructField(v, field, ignoreZero); ok {
+			encodedUser[field.name] = encoded
 		}
 	}
 	return encodedUser
 }
 
+func encodeUserPointer(id string) map[string]interface{} {
+	return map[string]interface{}{
+		"__type":    "Pointer",
+		"objectId":  id,
+		"className": "_User",
+	}
+}
+
 func encodeMap(fields interface{}, ignoreZero bool) map[string]interface{} {
 	encodedMap := make(map[string]interface{})
 	v := reflect.ValueOf(fields)
@@ -268,10 +229,13 @@ func encodeStruct(object interface{}, ignoreZero bool) map[string]interface{} {
 
 	if v.IsValid() && v.Kind() == reflect.Struct {
 		encodedMap := make(map[string]interface{})
-		for i := 0; i < v.NumField(); i++ {
-			encodedMap[t.Field(i).Name] = encode(v.Field(i).Interface(), ignoreZero)
-			if encodedMap[t.Field(i).Name] == nil {
-				delete(encodedMap, t.Field(i).Name)
+		for _, field := range structFields(t) {
+			fieldValue := fieldByIndex(v, field.index)
+			if !fieldValue.IsValid() || field.readOnly || (field.omitEmpty && fieldValue.IsZero()) {
+				continue
+			}
+			if encoded := encode(fieldValue.Interface(), ignoreZero); encoded != nil {
+				encodedMap[field.name] = encoded
 			}
 		}
 
@@ -281,6 +245,37 @@ func encodeStruct(object interface{}, ignoreZero bool) map[string]interface{} {
 	return nil
 }
 
+// encodeStructField encodes the field of Object or User, returns false if the field should be skipped
+func encodeStructField(v reflect.Value, field structField, ignoreZero bool) (interface{}, bool) {
+	if field.readOnly {
+		return nil, false
+	}
+
+	fieldValue := fieldByIndex(v, field.index)
+	if !fieldValue.IsValid() {
+		return nil, false
+	}
+
+	if field.omitEmpty && fieldValue.IsZero() {
+		return nil, false
+	}
+
+	if fieldValue.Kind() == reflect.Ptr || fieldValue.Kind() == reflect.Interface {
+		if fieldValue.IsNil() {
+			return nil, false
+		}
+	} else if ignoreZero && fieldValue.IsZero() {
+		return nil, false
+	}
+
+	encoded := encode(fieldValue.Interface(), ignoreZero)
+	if encoded == nil || reflect.ValueOf(encoded).IsZero() {
+		return nil, false
+	}
+
+	return encoded, true
+}
+
 func encodeArray(array interface{}, ignoreZero bool) []interface{} {
 	var encodedArray []interface{}
 	v := reflect.ValueOf(array)
@@ -338,9 +333,20 @@ func encodeFile(file *File, embedded bool) map[string]interface{} {
 }
 
 func encodeACL(acl *ACL) map[string]interface{} {
-	return map[string]interface{}{
-		"ACL": acl.content,
+	if acl.content == nil {
+		return nil
 	}
+
+	encodedACL := make(map[string]interface{})
+	for key, perms := range acl.content {
+		encodedPerms := make(map[string]interface{})
+		for perm, allowed := range perms {
+			encodedPerms[perm] = allowed
+		}
+		encodedACL[key] = encodedPerms
+	}
+
+	return encodedACL
 }
 
 func encodeAuthData(data *AuthData) interface{} {
@@ -371,30 +377,32 @@ func encodeRelation(relation *Relation) map[string]interface{} {
 }
 
 func bind(src reflect.Value, dst reflect.Value) error {
+	if ok, err := bindUnmarshaler(src, dst); ok {
+		return err
+	}
+
 	tdst := dst.Type()
 	switch dst.Kind() {
 	case reflect.Struct:
 		if src.Kind() == reflect.Map {
-			for i := 0; i < tdst.NumField(); i++ {
-				tag, ok := tdst.Field(i).Tag.Lookup("json")
-				if !ok || tag == "" {
-					tag = tdst.Field(i).Name
+			for _, field := range structFields(tdst) {
+				mapIndex := src.MapIndex(reflect.ValueOf(field.name))
+				if !mapIndex.IsValid() {
+					continue
 				}
-				mapIndex := src.MapIndex(reflect.ValueOf(tag))
-				if mapIndex.Kind() == reflect.Ptr && mapIndex.IsNil() {
+				if (mapIndex.Kind() == reflect.Ptr || mapIndex.Kind() == reflect.Interface) && mapIndex.IsNil() {
 					continue
 				}
-				if mapIndex.IsValid() {
-					if dst.Field(i).Kind() == reflect.Ptr && dst.Field(i).IsNil() {
-						pv := reflect.New(dst.Field(i).Type().Elem())
-						if err := bind(src.MapIndex(reflect.ValueOf(tag)), pv); err != nil {
-							return err
-						}
-						dst.Field(i).Set(pv)
-					} else {
-						if err := bind(src.MapIndex(reflect.ValueOf(tag)), dst.Field(i)); err != nil {
-							return err
-						}
+				fieldValue := fieldByIndexAlloc(dst, field.index)
+				if fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil() {
+					pv := reflect.New(fieldValue.Type().Elem())
+					if err := bind(mapIndex, pv); err != nil {
+						return err
+					}
+					fieldValue.Set(pv)
+				} else {
+					if err := bind(mapIndex, fieldValue); err != nil {
+						return err
 					}
 				}
 			}
@@ -633,6 +641,14 @@ func decodeObject(fields interface{}) (*Object, error) {
 		decodedFields["updatedAt"] = decodedUpdatedAt
 	}
 
+	if decodedFields["ACL"] != nil {
+		acl, err := decodeACLField(decodedFields["ACL"])
+		if err != nil {
+			return nil, err
+		}
+		decodedFields["ACL"] = acl
+	}
+
 	return &Object{
 		ID:        objectID,
 		CreatedAt: decodedCreatedAt,
@@ -798,55 +814,103 @@ func decodeFile(fields map[string]interface{}) (*File, error) {
 	}
 	file.ID = objectID
 
-	createdAt, ok := decodedFields["createdAt"].(string)
-	if !ok {
-		return nil, fmt.Errorf("unexpected error when parse createdAt: want type string but %v", reflect.TypeOf(decodedFields["createdAt"]))
-	}
-	decodedCreatedAt, err := time.Parse(time.RFC3339, createdAt)
-	if err != nil {
-		return nil, fmt.Errorf("unexpected error when parse createdAt: %v", err)
+	if decodedFields["createdAt"] != nil {
+		createdAt, ok := decodedFields["createdAt"].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected error when parse createdAt: want type string but %v", reflect.TypeOf(decodedFields["createdAt"]))
+		}
+		decodedCreatedAt, err := time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected error when parse createdAt: %v", err)
+		}
+		file.CreatedAt = decodedCreatedAt
+		decodedFields["createdAt"] = decodedCreatedAt
 	}
-	file.CreatedAt = decodedCreatedAt
 
-	updatedAt, ok := decodedFields["updatedAt"].(string)
-	if !ok {
-		return nil, fmt.Errorf("unexpected error when parse updatedAt: want type string but %v", reflect.TypeOf(decodedFields["updatedAt"]))
+	if decodedFields["updatedAt"] != nil {
+		updatedAt, ok := decodedFields["updatedAt"].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected error when parse updatedAt: want type string but %v", reflect.TypeOf(decodedFields["updatedAt"]))
+		}
+		decodedUpdatedAt, err := time.Parse(time.RFC3339, updatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected error when parse updatedAt: %v", err)
+		}
+		file.UpdatedAt = decodedUpdatedAt
+		decodedFields["updatedAt"] = decodedUpdatedAt
 	}
-	decodedUpdatedAt, err := time.Parse(time.RFC3339, updatedAt)
-	if err != nil {
-		return nil, fmt.Errorf("unexpected error when parse updatedAt: %v", err)
+
+	for key, field := range map[string]*string{
+		"key":       &file.Key,
+		"name":      &file.Name,
+		"url":       &file.URL,
+		"bucket":    &file.Bucket,
+		"provider":  &file.Provider,
+		"mime_type": &file.MIME,
+	} {
+		if decodedFields[key] == nil {
+			continue
+		}
+		value, ok := decodedFields[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected error when parse %s from response: want type string but %v", key, reflect.TypeOf(decodedFields[key]))
+		}
+		*field = value
 	}
-	file.UpdatedAt = decodedUpdatedAt
 
-	key, ok := decodedFields["key"].(string)
-	if !ok {
-		return nil, fmt.Errorf("unexpected error when parse key from response: want type string but %v", reflect.TypeOf(decodedFields["key"]))
+	if decodedFields["metaData"] != nil {
+		metadata, ok := decodedFields["metaData"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected error when parse metaData from response: want type map[string]interface{} but %v", reflect.TypeOf(decodedFields["metaData"]))
+		}
+		file.Meatadata = metadata
+		if size, ok := metadata["size"].(float64); ok {
+			file.Size = int64(size)
+		}
 	}
-	file.Key = key
 
-	url, ok := decodedFields["url"].(string)
-	if !ok {
-		return nil, fmt.Errorf("unexpected error when parse url from response: want type string but %v", reflect.TypeOf(decodedFields["url"]))
+	if decodedFields["ACL"] != nil {
+		acl, err := decodeACLField(decodedFields["ACL"])
+		if err != nil {
+			return nil, err
+		}
+		decodedFields["ACL"] = acl
 	}
-	file.URL = url
 
-	bucket, ok := decodedFields["bucket"].(string)
-	if !ok {
-		return nil, fmt.Errorf("unexpected error when parse bucket from response: want type string but %v", reflect.TypeOf(decodedFields["bucket"]))
+	return file, nil
+}
+
+func decodeACLField(field interface{}) (*ACL, error) {
+	if acl, ok := field.(*ACL); ok {
+		return acl, nil
 	}
-	file.Bucket = bucket
 
-	provider, ok := decodedFields["provider"].(string)
+	fields, ok := field.(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("unexpected error when parse provider from response: want type string but %v", reflect.TypeOf(decodedFields["provider"]))
+		return nil, fmt.Errorf("unexpected error when parse ACL from response: want type map[string]interface{} but %v", reflect.TypeOf(field))
 	}
-	file.Provider = provider
 
-	return file, nil
+	return decodeACL(fields)
 }
 
-func decodeACL(fields map[string]map[string]bool) (*ACL, error) {
-	return nil, nil
+func decodeACL(fields map[string]interface{}) (*ACL, error) {
+	acl := NewACL()
+	for key, value := range fields {
+		perms, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected error when parse ACL of %s: want type map[string]interface{} but %v", key, reflect.TypeOf(value))
+		}
+		acl.content[key] = make(map[string]bool)
+		for perm, allowed := range perms {
+			allowedBool, ok := allowed.(bool)
+			if !ok {
+				return nil, fmt.Errorf("unexpected error when parse ACL of %s: want type bool but %v", key, reflect.TypeOf(allowed))
+			}
+			acl.content[key][perm] = allowedBool
+		}
+	}
+
+	return acl, nil
 }
 
 func decodeOp(fields map[string]interface{}) (*Op, error) {
@@ -869,13 +933,3 @@ func decodeOp(fields map[string]interface{}) (*Op, error) {
 
 	return op, nil
 }
-
-func parseTag(tag string) (name string, option string) {
-	parts := strings.Split(tag, ",")
-
-	if len(parts) > 1 {
-		return parts[0], parts[1]
-	}
-
-	return parts[0], ""
-}
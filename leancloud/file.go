@@ -2,6 +2,7 @@ package leancloud
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -22,7 +23,7 @@ type File struct {
 	MIME      string                 `json:"mime_type"`
 	URL       string                 `json:"url"`
 	Size      int64                  `json:"size"`
-	Meatadata map[string]interface{} `json:"metadata"`
+	Meatadata map[string]interface{} `json:"metaData"`
 }
 
 func (file *File) fetchOwner(client *Client, authOptions ...AuthOption) (*User, error) {
@@ -129,36 +130,51 @@ func (file *File) fileCallback(result bool, token string, client *Client, authOp
 	return nil
 }
 
-func (file *File) uploadQiniu(token, uploadURL string, reader io.ReadSeeker) error {
+func (file *File) updateMetadata(client *Client, authOptions ...AuthOption) error {
+	path := fmt.Sprint("/1.1/files/", file.ID)
+	options := client.getRequestOptions()
+	options.JSON = map[string]interface{}{
+		"metaData": file.Meatadata,
+	}
+
+	_, err := client.request(methodPut, path, options, authOptions...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (file *File) uploadQiniu(ctx context.Context, token, uploadURL string, reader io.Reader) error {
 	out, in := io.Pipe()
 	part := multipart.NewWriter(in)
-	done := make(chan error)
+	done := make(chan error, 1)
 
 	go func() {
 		if err := part.WriteField("key", file.Key); err != nil {
-			in.Close()
+			in.CloseWithError(err)
 			done <- err
 			return
 		}
 		if err := part.WriteField("token", token); err != nil {
-			in.Close()
+			in.CloseWithError(err)
 			done <- err
 			return
 		}
 		writer, err := part.CreateFormFile("file", file.Name)
 		if err != nil {
-			in.Close()
+			in.CloseWithError(err)
 			done <- err
 			return
 		}
 		_, err = io.Copy(writer, reader)
 		if err != nil {
-			in.Close()
+			in.CloseWithError(err)
 			done <- err
 			return
 		}
 		if err := part.Close(); err != nil {
-			in.Close()
+			in.CloseWithError(err)
 			done <- err
 			return
 		}
@@ -166,16 +182,19 @@ func (file *File) uploadQiniu(token, uploadURL string, reader io.ReadSeeker) err
 		done <- nil
 	}()
 
-	req, err := http.NewRequest("POST", "https://up.qbox.me/", out)
-	req.Header.Set("Content-Type", part.FormDataContentType())
+	req, err := http.NewRequestWithContext(ctx, "POST", uploadURL, out)
 	if err != nil {
+		out.CloseWithError(err)
 		return err
 	}
+	req.Header.Set("Content-Type", part.FormDataContentType())
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("unexpected error when upload file to Qiniu: %v", err)
+		out.CloseWithError(err)
+		return fmt.Errorf("unexpected error when upload file to Qiniu: %w", err)
 	}
+	defer resp.Body.Close()
 
 	err = <-done
 	if err != nil {
@@ -193,8 +212,8 @@ func (file *File) uploadQiniu(token, uploadURL string, reader io.ReadSeeker) err
 	return nil
 }
 
-func (file *File) uploadS3(token, uploadURL string, reader io.ReadSeeker) error {
-	req, err := http.NewRequest("PUT", uploadURL, reader)
+func (file *File) uploadS3(ctx context.Context, token, uploadURL string, reader io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, "PUT", uploadURL, reader)
 	if err != nil {
 		return err
 	}
@@ -220,60 +239,37 @@ func (file *File) uploadS3(token, uploadURL string, reader io.ReadSeeker) error
 	return nil
 }
 
-func (file *File) uploadCOS(token, uploadURL string, reader io.ReadSeeker) error {
-	out, in := io.Pipe()
-	part := multipart.NewWriter(in)
-	done := make(chan error)
-
-	go func() {
-		if err := part.WriteField("op", "upload"); err != nil {
-			in.Close()
-			done <- err
-			return
-		}
-		writer, err := part.CreateFormFile("fileContent", file.Name)
-		if err != nil {
-			in.Close()
-			done <- err
-			return
-		}
-		_, err = io.Copy(writer, reader)
-		if err != nil {
-			in.Close()
-			done <- err
-			return
-		}
-		if err := part.Close(); err != nil {
-			in.Close()
-			done <- err
-			return
-		}
-		in.Close()
-		done <- nil
-	}()
-
-	body, err := ioutil.ReadAll(out)
-	if err != nil {
+// uploadCOS sends the file in a multipart form, the length of which is set if size is known, or it is streamed
+func (file *File) uploadCOS(ctx context.Context, token, uploadURL string, reader io.Reader, size int64) error {
+	form := new(bytes.Buffer)
+	part := multipart.NewWriter(form)
+	if err := part.WriteField("op", "upload"); err != nil {
 		return fmt.Errorf("unexpected error when upload file to COS: %v", err)
 	}
-
-	req, err := http.NewRequest("POST", uploadURL+"?sign="+url.QueryEscape(token), bytes.NewBuffer(body))
-	if err != nil {
+	if _, err := part.CreateFormFile("fileContent", file.Name); err != nil {
 		return fmt.Errorf("unexpected error when upload file to COS: %v", err)
 	}
+	header := form.Len()
+	if err := part.Close(); err != nil {
+		return fmt.Errorf("unexpected error when upload file to COS: %v", err)
+	}
+	formBytes := form.Bytes()
 
-	req.Header.Set("Content-Type", part.FormDataContentType())
-	req.ContentLength = int64(len(body))
-
-	resp, err := http.DefaultClient.Do(req)
+	body := io.MultiReader(bytes.NewReader(formBytes[:header]), reader, bytes.NewReader(formBytes[header:]))
+	req, err := http.NewRequestWithContext(ctx, "POST", uploadURL+"?sign="+url.QueryEscape(token), body)
 	if err != nil {
 		return fmt.Errorf("unexpected error when upload file to COS: %v", err)
 	}
+	req.Header.Set("Content-Type", part.FormDataContentType())
+	if size >= 0 {
+		req.ContentLength = int64(len(formBytes)) + size
+	}
 
-	err = <-done
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("unexpected error when upload file to COS: %v", err)
 	}
+	defer resp.Body.Close()
 
 	content, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -0,0 +1,95 @@
+package leancloud
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"image"
+	_ "image/gif"  // register GIF for image.DecodeConfig
+	_ "image/jpeg" // register JPEG for image.DecodeConfig
+	_ "image/png"  // register PNG for image.DecodeConfig
+	"io"
+	"net/http"
+	"strings"
+)
+
+// metadataPeekSize is how many leading bytes are inspected to detect MIME type and image dimensions
+const metadataPeekSize = 256 << 10
+
+// fileChecksums computes checksums of the content written to it
+type fileChecksums struct {
+	md5    hash.Hash
+	sha256 hash.Hash
+}
+
+func newFileChecksums() *fileChecksums {
+	return &fileChecksums{
+		md5:    md5.New(),
+		sha256: sha256.New(),
+	}
+}
+
+func (checksums *fileChecksums) Write(p []byte) (int, error) {
+	checksums.md5.Write(p)
+	return checksums.sha256.Write(p)
+}
+
+// computeChecksums computes checksums of the reader ahead of the upload if it could be read again,
+// i.e. it is seekable or a bytes.Buffer, and returns nil otherwise. Seekers are restored to where they were
+func computeChecksums(reader io.Reader) (*fileChecksums, error) {
+	checksums := newFileChecksums()
+	switch reader := reader.(type) {
+	case *bytes.Buffer:
+		checksums.Write(reader.Bytes())
+	case io.ReadSeeker:
+		offset, err := reader.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(checksums, reader); err != nil {
+			return nil, err
+		}
+		if _, err := reader.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	return checksums, nil
+}
+
+// record puts checksums into metadata, _checksum is the MD5 recognized by other LeanCloud SDKs
+func (checksums *fileChecksums) record(metadata map[string]interface{}) {
+	metadata["_checksum"] = hex.EncodeToString(checksums.md5.Sum(nil))
+	metadata["_sha256"] = hex.EncodeToString(checksums.sha256.Sum(nil))
+}
+
+// detectMetadata sniffs MIME type if unset and dimensions of images from leading bytes of the reader,
+// the returned reader should be used in place of the given one
+func (file *File) detectMetadata(reader io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReaderSize(reader, metadataPeekSize)
+	head, err := buffered.Peek(metadataPeekSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+
+	if file.MIME == "" {
+		file.MIME = http.DetectContentType(head)
+	}
+
+	if strings.HasPrefix(file.MIME, "image/") {
+		if config, _, err := image.DecodeConfig(bytes.NewReader(head)); err == nil {
+			if file.Meatadata == nil {
+				file.Meatadata = make(map[string]interface{})
+			}
+			file.Meatadata["width"] = config.Width
+			file.Meatadata["height"] = config.Height
+		}
+	}
+
+	return buffered, nil
+}
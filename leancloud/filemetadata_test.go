@@ -0,0 +1,87 @@
+package leancloud
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestFileDetectMetadata(t *testing.T) {
+	t.Run("Image", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 16, 9))); err != nil {
+			t.Fatal(err)
+		}
+		content := buf.Bytes()
+
+		file := new(File)
+		reader, err := file.detectMetadata(bytes.NewReader(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if file.MIME != "image/png" {
+			t.Fatalf("unexpected MIME: %s", file.MIME)
+		}
+		if file.Meatadata["width"] != 16 || file.Meatadata["height"] != 9 {
+			t.Fatalf("unexpected dimensions: %v", file.Meatadata)
+		}
+
+		read, err := ioutil.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(read, content) {
+			t.Fatal("content changed by detection")
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		file := new(File)
+		if _, err := file.detectMetadata(bytes.NewReader([]byte("temporary file's content"))); err != nil {
+			t.Fatal(err)
+		}
+
+		if file.MIME != "text/plain; charset=utf-8" {
+			t.Fatalf("unexpected MIME: %s", file.MIME)
+		}
+	})
+
+	t.Run("Checksum", func(t *testing.T) {
+		checksums := newFileChecksums()
+		checksums.Write([]byte("temporary file's content"))
+		metadata := make(map[string]interface{})
+		checksums.record(metadata)
+
+		if len(metadata["_checksum"].(string)) != 32 || len(metadata["_sha256"].(string)) != 64 {
+			t.Fatalf("unexpected checksums: %v", metadata)
+		}
+	})
+
+	t.Run("ComputeChecksums", func(t *testing.T) {
+		reader := bytes.NewReader([]byte("temporary file's content"))
+		reader.Seek(10, io.SeekStart)
+		checksums, err := computeChecksums(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if checksums == nil {
+			t.Fatal("checksums of seeker not computed")
+		}
+		if rest, _ := ioutil.ReadAll(reader); string(rest) != "file's content" {
+			t.Fatalf("seeker not restored: %s", string(rest))
+		}
+
+		buffer := bytes.NewBufferString("temporary file's content")
+		if checksums, err := computeChecksums(buffer); err != nil || checksums == nil || buffer.Len() != 24 {
+			t.Fatal("checksums of buffer not computed or buffer consumed")
+		}
+
+		if checksums, err := computeChecksums(struct{ io.Reader }{buffer}); err != nil || checksums != nil {
+			t.Fatal("checksums of stream computed ahead")
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package leancloud
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -28,28 +29,62 @@ func (ref *Files) NewQuery() *Query {
 
 // Upload transfer the file to cloud storage and create a File object in _File class
 func (ref *Files) Upload(file *File, reader io.ReadSeeker, authOptions ...AuthOption) error {
+	return ref.UploadWithOptions(file, reader, nil, authOptions...)
+}
+
+// UploadWithOptions transfer the file to cloud storage with progress reporting and cancellation,
+// and create a File object in _File class
+func (ref *Files) UploadWithOptions(file *File, reader io.ReadSeeker, options *UploadOptions, authOptions ...AuthOption) error {
 	size, err := getSeekerSize(reader)
 	if err != nil {
 		return fmt.Errorf("unexpected error when get length of file: %v", err)
 	}
 
-	owner, err := file.fetchOwner(ref.c, authOptions...)
+	return ref.UploadFromReader(file, reader, size, options, authOptions...)
+}
+
+// UploadFromReader streams the file from reader to cloud storage and create a File object in _File class.
+// size should be -1 if unknown, the size will then be counted while uploading and recorded before the upload is confirmed.
+func (ref *Files) UploadFromReader(file *File, reader io.Reader, size int64, options *UploadOptions, authOptions ...AuthOption) error {
+	ctx := options.context()
+
+	if size < 0 && file.Size > 0 {
+		size = file.Size
+	}
+
+	// checksums of readers which could be read again are recorded along with the file,
+	// the others are computed while uploading and recorded after that
+	var checksums *fileChecksums
+	if options != nil && options.Checksum {
+		precomputed, err := computeChecksums(reader)
+		if err != nil {
+			return fmt.Errorf("unexpected error when read file: %v", err)
+		}
+		if precomputed != nil {
+			if file.Meatadata == nil {
+				file.Meatadata = make(map[string]interface{})
+			}
+			precomputed.record(file.Meatadata)
+		} else {
+			checksums = newFileChecksums()
+		}
+	}
+
+	reader, err := file.detectMetadata(reader)
 	if err != nil {
-		return fmt.Errorf("unexpected error when fetch owner: %v", err)
+		return fmt.Errorf("unexpected error when read file: %v", err)
 	}
 
-	if file.Size == 0 {
-		file.Size = size
+	if checksums != nil {
+		reader = io.TeeReader(reader, checksums)
 	}
 
-	if reflect.ValueOf(file.Meatadata).IsNil() {
-		file.Meatadata = make(map[string]interface{})
+	if err := ref.prepareMetadata(file, size, authOptions...); err != nil {
+		return err
 	}
-	file.Meatadata["size"] = file.Size
-	if owner != nil {
-		file.Meatadata["owner"] = owner.ID
-	} else {
-		file.Meatadata["owner"] = "unknown"
+
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	token, uploadURL, err := file.fetchToken(ref.c, authOptions...)
@@ -57,28 +92,37 @@ func (ref *Files) Upload(file *File, reader io.ReadSeeker, authOptions ...AuthOp
 		return err
 	}
 
-	switch file.Provider {
-	case "qiniu":
-		if err := file.uploadQiniu(token, "https://up.qbox.me/", reader); err != nil {
-			if err := file.fileCallback(false, token, ref.c, authOptions...); err != nil {
-				return err
-			}
-			return err
+	body := newProgressReader(reader, size, options)
+	provider, err := getStorageProvider(file.Provider)
+	if err == nil {
+		err = provider.Upload(ctx, &StorageUpload{
+			File:      file,
+			Token:     token,
+			UploadURL: uploadURL,
+			Reader:    body,
+			Size:      size,
+			Client:    ref.c,
+		})
+	}
+
+	if err == nil && (size < 0 || checksums != nil) {
+		// metadata counted while uploading is recorded before the upload is confirmed,
+		// so that a failure leaves no file to be uploaded again
+		if size < 0 {
+			file.Size = body.sent
+			file.Meatadata["size"] = file.Size
 		}
-	case "s3":
-		if err := file.uploadS3(token, uploadURL, reader); err != nil {
-			if err := file.fileCallback(false, token, ref.c, authOptions...); err != nil {
-				return err
-			}
-			return err
+		if checksums != nil {
+			checksums.record(file.Meatadata)
 		}
-	case "qcloud":
-		if err := file.uploadCOS(token, uploadURL, reader); err != nil {
-			if err := file.fileCallback(false, token, ref.c, authOptions...); err != nil {
-				return err
-			}
+		err = file.updateMetadata(ref.c, authOptions...)
+	}
+
+	if err != nil {
+		if err := file.fileCallback(false, token, ref.c, authOptions...); err != nil {
 			return err
 		}
+		return err
 	}
 
 	if err := file.fileCallback(true, token, ref.c, authOptions...); err != nil {
@@ -88,6 +132,49 @@ func (ref *Files) Upload(file *File, reader io.ReadSeeker, authOptions ...AuthOp
 	return nil
 }
 
+func (ref *Files) prepareMetadata(file *File, size int64, authOptions ...AuthOption) error {
+	owner, err := file.fetchOwner(ref.c, authOptions...)
+	if err != nil {
+		return fmt.Errorf("unexpected error when fetch owner: %v", err)
+	}
+
+	if reflect.ValueOf(file.Meatadata).IsNil() {
+		file.Meatadata = make(map[string]interface{})
+	}
+
+	if size >= 0 {
+		if file.Size == 0 {
+			file.Size = size
+		}
+		file.Meatadata["size"] = file.Size
+	}
+	if owner != nil {
+		file.Meatadata["owner"] = owner.ID
+	} else {
+		file.Meatadata["owner"] = "unknown"
+	}
+
+	return nil
+}
+
+// Destroy deletes the File object from _File class, and the file from storage if its provider implements StorageDeleter
+func (ref *Files) Destroy(file *File, authOptions ...AuthOption) error {
+	if err := ref.c.File(file.ID).Destroy(authOptions...); err != nil {
+		return err
+	}
+
+	provider, err := getStorageProvider(file.Provider)
+	if err != nil {
+		return nil
+	}
+
+	if deleter, ok := provider.(StorageDeleter); ok {
+		return deleter.Delete(context.Background(), file)
+	}
+
+	return nil
+}
+
 // UploadFromURL create an object of file in _File class with given file's url
 func (ref *Files) UploadFromURL(file *File, authOptions ...AuthOption) error {
 	if reflect.ValueOf(file.Meatadata).IsNil() {
@@ -145,11 +232,7 @@ func (ref *Files) UploadFromLocalFile(file *File, path string, authOptions ...Au
 	}
 
 	if file.MIME == "" {
-		mime := mime.TypeByExtension(filepath.Ext(path))
-		if mime == "" {
-			mime = "application/octet-stream"
-		}
-		file.MIME = mime
+		file.MIME = mime.TypeByExtension(filepath.Ext(path))
 	}
 
 	f, err := os.Open(path)
@@ -1,11 +1,20 @@
 package leancloud
 
 import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"mime"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/levigross/grequests"
@@ -54,6 +63,86 @@ func checkUpload(url string) error {
 
 	return nil
 }
+
+// uploadServer serves the file API of LeanCloud, and the storage under /storage
+type uploadServer struct {
+	*httptest.Server
+	mutex     sync.Mutex
+	provider  string
+	uploadURL string
+	requests  []string
+	callbacks []bool
+	metadata  map[string]interface{}
+	storage   http.HandlerFunc
+
+	// tokenMetadata is metadata of the file sent along with the request of fileTokens
+	tokenMetadata map[string]interface{}
+
+	// metadataError fails requests updating metadata if set
+	metadataError bool
+}
+
+func newUploadServer(t *testing.T, provider, uploadPath string, storage http.HandlerFunc) *uploadServer {
+	server := &uploadServer{
+		provider: provider,
+		storage:  storage,
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mutex.Lock()
+		server.requests = append(server.requests, fmt.Sprint(r.Method, " ", r.URL.Path))
+		server.mutex.Unlock()
+
+		switch r.URL.Path {
+		case "/1.1/fileTokens":
+			body := decodeRequestJSON(t, r)
+			server.mutex.Lock()
+			server.tokenMetadata, _ = body["metaData"].(map[string]interface{})
+			server.mutex.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"objectId":"file1","createdAt":"2020-08-31T03:01:30.654Z","key":"key/test.bin","url":"https://example.com/test.bin",`+
+				`"token":"token1","bucket":"","upload_url":"%s","provider":"%s"}`, server.uploadURL, server.provider)
+		case "/1.1/files/file1":
+			body := decodeRequestJSON(t, r)
+			w.Header().Set("Content-Type", "application/json")
+			if server.metadataError {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"code":1,"error":"internal error"}`))
+				return
+			}
+			server.mutex.Lock()
+			server.metadata, _ = body["metaData"].(map[string]interface{})
+			server.mutex.Unlock()
+			w.Write([]byte(`{}`))
+		case "/1.1/fileCallback":
+			body := decodeRequestJSON(t, r)
+			server.mutex.Lock()
+			server.callbacks = append(server.callbacks, body["result"] == true)
+			server.mutex.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		default:
+			if strings.HasPrefix(r.URL.Path, "/storage") {
+				server.storage(w, r)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":404,"error":"not found"}`))
+		}
+	}))
+	server.uploadURL = fmt.Sprint(server.URL, uploadPath)
+
+	return server
+}
+
+func (server *uploadServer) client(credentials *S3Credentials) *Client {
+	return NewClient(&ClientOptions{
+		AppID:         "app",
+		AppKey:        "key",
+		ServerURL:     server.URL,
+		S3Credentials: credentials,
+	})
+}
+
 func TestFilesUpload(t *testing.T) {
 	filename, err := generateTempFile("go-sdk-file-upload-*.txt")
 	if err != nil {
@@ -152,3 +241,124 @@ func TestFilesUploadFromFile(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestFilesUploadFromReader(t *testing.T) {
+	content := []byte("temporary file's content")
+	var contentLength int64
+	var transferEncoding []string
+	storage := func(w http.ResponseWriter, r *http.Request) {
+		contentLength, transferEncoding = r.ContentLength, r.TransferEncoding
+		if r.URL.Query().Get("sign") != "token1" {
+			t.Errorf("unexpected sign %s", r.URL.RawQuery)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error(err)
+			return
+		}
+		f, _, err := r.FormFile("fileContent")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer f.Close()
+		if received, _ := ioutil.ReadAll(f); !bytes.Equal(received, content) || r.FormValue("op") != "upload" {
+			t.Errorf("dismatch form %s", string(received))
+		}
+	}
+
+	t.Run("KnownSize", func(t *testing.T) {
+		server := newUploadServer(t, "qcloud", "/storage/key", storage)
+		defer server.Close()
+
+		file := &File{Name: "test.txt"}
+		if err := server.client(nil).Files.UploadFromReader(file, bytes.NewReader(content), int64(len(content)), nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if contentLength <= int64(len(content)) || len(transferEncoding) != 0 {
+			t.Fatalf("unexpected length %d of form %v", contentLength, transferEncoding)
+		}
+		if server.metadata != nil || !reflect.DeepEqual(server.callbacks, []bool{true}) {
+			t.Fatalf("unexpected requests %v", server.requests)
+		}
+		if file.ID != "file1" || file.Size != int64(len(content)) {
+			t.Fatalf("dismatch file %v", file)
+		}
+	})
+
+	t.Run("UnknownSize", func(t *testing.T) {
+		server := newUploadServer(t, "qcloud", "/storage/key", storage)
+		defer server.Close()
+
+		file := &File{Name: "test.txt"}
+		reader := struct{ io.Reader }{bytes.NewReader(content)}
+		if err := server.client(nil).Files.UploadFromReader(file, reader, -1, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if contentLength != -1 || !reflect.DeepEqual(transferEncoding, []string{"chunked"}) {
+			t.Fatalf("form of unknown size not streamed: %d %v", contentLength, transferEncoding)
+		}
+		if file.Size != int64(len(content)) || server.metadata["size"] != float64(len(content)) {
+			t.Fatalf("size not recorded: %d %v", file.Size, server.metadata)
+		}
+		wantRequests := []string{"POST /1.1/fileTokens", "POST /storage/key", "PUT /1.1/files/file1", "POST /1.1/fileCallback"}
+		if !reflect.DeepEqual(server.requests, wantRequests) || !reflect.DeepEqual(server.callbacks, []bool{true}) {
+			t.Fatalf("unexpected requests %v", server.requests)
+		}
+	})
+
+	md5sum := md5.Sum(content)
+	checksum := hex.EncodeToString(md5sum[:])
+
+	t.Run("Checksum", func(t *testing.T) {
+		server := newUploadServer(t, "qcloud", "/storage/key", storage)
+		defer server.Close()
+
+		file := &File{Name: "test.txt"}
+		options := &UploadOptions{Checksum: true}
+		if err := server.client(nil).Files.UploadFromReader(file, bytes.NewReader(content), int64(len(content)), options); err != nil {
+			t.Fatal(err)
+		}
+
+		if server.tokenMetadata["_checksum"] != checksum || len(server.tokenMetadata["_sha256"].(string)) != 64 {
+			t.Fatalf("checksums not sent with the file: %v", server.tokenMetadata)
+		}
+		if server.metadata != nil {
+			t.Fatalf("unexpected update of metadata %v", server.metadata)
+		}
+	})
+
+	t.Run("ChecksumOfStream", func(t *testing.T) {
+		server := newUploadServer(t, "qcloud", "/storage/key", storage)
+		defer server.Close()
+
+		file := &File{Name: "test.txt"}
+		reader := struct{ io.Reader }{bytes.NewReader(content)}
+		options := &UploadOptions{Checksum: true}
+		if err := server.client(nil).Files.UploadFromReader(file, reader, -1, options); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := server.tokenMetadata["_checksum"]; ok {
+			t.Fatalf("checksums of stream sent before upload: %v", server.tokenMetadata)
+		}
+		if server.metadata["_checksum"] != checksum || server.metadata["size"] != float64(len(content)) {
+			t.Fatalf("checksums of stream not recorded: %v", server.metadata)
+		}
+	})
+
+	t.Run("MetadataError", func(t *testing.T) {
+		server := newUploadServer(t, "qcloud", "/storage/key", storage)
+		defer server.Close()
+		server.metadataError = true
+
+		reader := struct{ io.Reader }{bytes.NewReader(content)}
+		if err := server.client(nil).Files.UploadFromReader(&File{Name: "test.txt"}, reader, -1, nil); err == nil {
+			t.Fatal("upload succeeded without metadata")
+		}
+		if !reflect.DeepEqual(server.callbacks, []bool{false}) {
+			t.Fatalf("failure not reported: %v", server.callbacks)
+		}
+	})
+}
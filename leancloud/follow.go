@@ -0,0 +1,170 @@
+package leancloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// FollowQuery contains parameters of queries on followers or followees of a user
+type FollowQuery struct {
+	ref   *UserRef
+	key   string
+	skip  int
+	limit int
+}
+
+// Follow makes the referred user follow the target user, with optional attributes of the friendship
+func (ref *UserRef) Follow(targetID string, attributes map[string]interface{}, authOptions ...AuthOption) error {
+	if ref == nil || ref.ID == "" || ref.class == "" {
+		return fmt.Errorf("unable to follow %s: no user referred", targetID)
+	}
+
+	if targetID == "" {
+		return fmt.Errorf("unable to follow: targetID is empty")
+	}
+
+	path := fmt.Sprint("/1.1/users/", ref.ID, "/friendship/", targetID)
+	options := ref.c.getRequestOptions()
+	if attributes != nil {
+		options.JSON = encodeMap(attributes, false)
+	}
+
+	if _, err := ref.c.request(methodPost, path, options, authOptions...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Unfollow makes the referred user stop following the target user
+func (ref *UserRef) Unfollow(targetID string, authOptions ...AuthOption) error {
+	if ref == nil || ref.ID == "" || ref.class == "" {
+		return fmt.Errorf("unable to unfollow %s: no user referred", targetID)
+	}
+
+	if targetID == "" {
+		return fmt.Errorf("unable to unfollow: targetID is empty")
+	}
+
+	path := fmt.Sprint("/1.1/users/", ref.ID, "/friendship/", targetID)
+	if _, err := ref.c.request(methodDelete, path, ref.c.getRequestOptions(), authOptions...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewFollowerQuery constructs a query on users following the referred user
+func (ref *UserRef) NewFollowerQuery() *FollowQuery {
+	return &FollowQuery{
+		ref: ref,
+		key: "follower",
+	}
+}
+
+// NewFolloweeQuery constructs a query on users followed by the referred user
+func (ref *UserRef) NewFolloweeQuery() *FollowQuery {
+	return &FollowQuery{
+		ref: ref,
+		key: "followee",
+	}
+}
+
+func (q *FollowQuery) Skip(count int) *FollowQuery {
+	q.skip = count
+	return q
+}
+
+func (q *FollowQuery) Limit(limit int) *FollowQuery {
+	q.limit = limit
+	return q
+}
+
+// Find fetch followers or followees into users, which should be a pointer to a slice of User or structures embedding User
+func (q *FollowQuery) Find(users interface{}, authOptions ...AuthOption) error {
+	respJSON, err := q.do(false, authOptions...)
+	if err != nil {
+		return err
+	}
+
+	results, ok := respJSON["results"].([]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected error when parse results from response: want type []interface{} but %v", reflect.TypeOf(respJSON["results"]))
+	}
+
+	var decodedUsers []interface{}
+	for _, result := range results {
+		fields, ok := result.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected error when parse result from response: want type map[string]interface{} but %v", reflect.TypeOf(result))
+		}
+		pointer, ok := fields[q.key].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected error when parse %s from response: want type map[string]interface{} but %v", q.key, reflect.TypeOf(fields[q.key]))
+		}
+		decodedUser, err := decodePointer(pointer)
+		if err != nil {
+			return err
+		}
+		decodedUsers = append(decodedUsers, decodedUser)
+	}
+
+	if err := bind(reflect.ValueOf(decodedUsers), reflect.ValueOf(users).Elem()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Count returns the number of followers or followees
+func (q *FollowQuery) Count(authOptions ...AuthOption) (int, error) {
+	respJSON, err := q.do(true, authOptions...)
+	if err != nil {
+		return 0, err
+	}
+
+	count, ok := respJSON["count"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("unable to parse count from response")
+	}
+
+	return int(count), nil
+}
+
+func (q *FollowQuery) do(count bool, authOptions ...AuthOption) (map[string]interface{}, error) {
+	if q.ref == nil || q.ref.ID == "" {
+		return nil, fmt.Errorf("unable to query %ss: no user referred", q.key)
+	}
+
+	path := fmt.Sprint("/1.1/users/", q.ref.ID, "/", q.key, "s")
+	options := q.ref.c.getRequestOptions()
+	options.Params = map[string]string{
+		"include": q.key,
+	}
+
+	if q.skip != 0 {
+		options.Params["skip"] = fmt.Sprintf("%d", q.skip)
+	}
+
+	if q.limit != 0 {
+		options.Params["limit"] = fmt.Sprintf("%d", q.limit)
+	}
+
+	if count {
+		options.Params["count"] = "1"
+		options.Params["limit"] = "0"
+	}
+
+	resp, err := q.ref.c.request(methodGet, path, options, authOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	respJSON := make(map[string]interface{})
+	if err := json.Unmarshal(resp.Bytes(), &respJSON); err != nil {
+		return nil, fmt.Errorf("unable to parse response %w", err)
+	}
+
+	return respJSON, nil
+}
@@ -0,0 +1,105 @@
+package leancloud
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFollow(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/1.1/users/user1/friendship/user2":
+			if r.Method == "POST" {
+				body, _ := ioutil.ReadAll(r.Body)
+				if string(body) != `{"group":"friends"}` {
+					t.Errorf("unexpected attributes %s", string(body))
+				}
+			}
+			w.Write([]byte(`{}`))
+		case r.URL.Path == "/1.1/users/user1/followers" || r.URL.Path == "/1.1/users/user1/followees":
+			key := r.URL.Path[len("/1.1/users/user1/") : len(r.URL.Path)-1]
+			if r.URL.Query().Get("include") != key {
+				t.Errorf("%s not included", key)
+			}
+			if r.URL.Query().Get("count") == "1" {
+				if r.URL.Query().Get("limit") != "0" {
+					t.Errorf("results not skipped when counting")
+				}
+				w.Write([]byte(`{"results":[],"count":2}`))
+				return
+			}
+			if r.URL.Query().Get("skip") != "1" || r.URL.Query().Get("limit") != "2" {
+				t.Errorf("unexpected paging %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"results":[` +
+				`{"` + key + `":{"__type":"Pointer","className":"_User","objectId":"user2","username":"bob","createdAt":"2020-08-31T03:01:30.654Z","updatedAt":"2020-08-31T03:01:30.654Z"}},` +
+				`{"` + key + `":{"__type":"Pointer","className":"_User","objectId":"user3","username":"carol","createdAt":"2020-08-31T03:01:30.654Z","updatedAt":"2020-08-31T03:01:30.654Z"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":404,"error":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		AppID:     "app",
+		AppKey:    "key",
+		ServerURL: server.URL,
+	})
+	ref := client.Users.ID("user1")
+
+	if err := ref.Follow("user2", map[string]interface{}{"group": "friends"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ref.Unfollow("user2"); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 || requests[0] != "POST /1.1/users/user1/friendship/user2" || requests[1] != "DELETE /1.1/users/user1/friendship/user2" {
+		t.Fatalf("unexpected requests %v", requests)
+	}
+
+	for _, query := range []*FollowQuery{ref.NewFollowerQuery(), ref.NewFolloweeQuery()} {
+		var users []User
+		if err := query.Skip(1).Limit(2).Find(&users); err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 2 || users[0].ID != "user2" || users[1].ID != "user3" {
+			t.Fatalf("dismatch users %v", users)
+		}
+
+		count, err := query.Count()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Fatalf("unexpected count %d", count)
+		}
+	}
+}
+
+func TestFollowWithoutUser(t *testing.T) {
+	client := NewClient(&ClientOptions{
+		AppID:     "app",
+		AppKey:    "key",
+		ServerURL: "http://127.0.0.1:1",
+	})
+
+	var ref *UserRef
+	if err := ref.Follow("user2", nil); err == nil {
+		t.Fatal("nil ref followed")
+	}
+	if err := client.Users.ID("").Unfollow("user2"); err == nil {
+		t.Fatal("empty ref unfollowed")
+	}
+	if err := client.Users.ID("user1").Follow("", nil); err == nil {
+		t.Fatal("empty target followed")
+	}
+	if _, err := client.Users.ID("").NewFollowerQuery().Count(); err == nil {
+		t.Fatal("followers of empty ref counted")
+	}
+}
@@ -1,6 +1,18 @@
 package leancloud
 
-import "math"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	earthRadiusInKilometers = 6371.0
+	earthRadiusInMiles      = 3958.8
+)
+
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
 
 // GeoPoint contains location's latitude and longitude
 type GeoPoint struct {
@@ -8,6 +20,32 @@ type GeoPoint struct {
 	Longitude float64 `json:"longitude"`
 }
 
+// NewGeoPoint constructs a GeoPoint after validating the coordinates
+func NewGeoPoint(latitude, longitude float64) (*GeoPoint, error) {
+	point := &GeoPoint{
+		Latitude:  latitude,
+		Longitude: longitude,
+	}
+	if err := point.Validate(); err != nil {
+		return nil, err
+	}
+
+	return point, nil
+}
+
+// Validate checks latitude is in [-90, 90] and longitude is in [-180, 180]
+func (point *GeoPoint) Validate() error {
+	if math.IsNaN(point.Latitude) || point.Latitude < -90 || point.Latitude > 90 {
+		return fmt.Errorf("invalid latitude %v: should be in [-90, 90]", point.Latitude)
+	}
+
+	if math.IsNaN(point.Longitude) || point.Longitude < -180 || point.Longitude > 180 {
+		return fmt.Errorf("invalid longitude %v: should be in [-180, 180]", point.Longitude)
+	}
+
+	return nil
+}
+
 // RadiansTo return the distance from this GeoPoint to another in radians
 func (point *GeoPoint) RadiansTo(target *GeoPoint) float64 {
 	radius := math.Pi / 180.0
@@ -23,7 +61,7 @@ func (point *GeoPoint) RadiansTo(target *GeoPoint) float64 {
 	latSinDelta := math.Sin(deltaLat / 2.0)
 	longSinDelta := math.Sin(deltaLong / 2.0)
 
-	a := (latSinDelta * longSinDelta) + (math.Cos(startLatRadius) * math.Cos(endLatRadius) * longSinDelta * longSinDelta)
+	a := (latSinDelta * latSinDelta) + (math.Cos(startLatRadius) * math.Cos(endLatRadius) * longSinDelta * longSinDelta)
 
 	a = math.Min(1.0, a)
 
@@ -32,10 +70,154 @@ func (point *GeoPoint) RadiansTo(target *GeoPoint) float64 {
 
 // KilometersTo return the distance from this GeoPoint to another in kilometers
 func (point *GeoPoint) KilometersTo(target *GeoPoint) float64 {
-	return (point.RadiansTo(target) * 6371.0)
+	return (point.RadiansTo(target) * earthRadiusInKilometers)
 }
 
 // MilesTo return the distance from this GeoPoint to another in miles
 func (point *GeoPoint) MilesTo(target *GeoPoint) float64 {
-	return (point.RadiansTo(target) * 3958.8)
+	return (point.RadiansTo(target) * earthRadiusInMiles)
+}
+
+// BoundingBoxInKilometers returns the southwest and northeast corners of the smallest box containing
+// all points within distance kilometers of this GeoPoint. The longitude of southwest is greater than
+// the one of northeast if the box crosses the antimeridian
+func (point *GeoPoint) BoundingBoxInKilometers(distance float64) (*GeoPoint, *GeoPoint) {
+	return point.boundingBox(distance / earthRadiusInKilometers)
+}
+
+// BoundingBoxInMiles is BoundingBoxInKilometers in miles
+func (point *GeoPoint) BoundingBoxInMiles(distance float64) (*GeoPoint, *GeoPoint) {
+	return point.boundingBox(distance / earthRadiusInMiles)
+}
+
+func (point *GeoPoint) boundingBox(radians float64) (*GeoPoint, *GeoPoint) {
+	lat := point.Latitude * math.Pi / 180
+	long := point.Longitude * math.Pi / 180
+
+	minLat, maxLat := lat-radians, lat+radians
+	var minLong, maxLong float64
+	if minLat > -math.Pi/2 && maxLat < math.Pi/2 {
+		deltaLong := math.Asin(math.Sin(radians) / math.Cos(lat))
+		minLong, maxLong = long-deltaLong, long+deltaLong
+		if minLong < -math.Pi {
+			minLong += 2 * math.Pi
+		}
+		if maxLong > math.Pi {
+			maxLong -= 2 * math.Pi
+		}
+	} else {
+		// a pole is within the distance, so are all longitudes
+		minLat, maxLat = math.Max(minLat, -math.Pi/2), math.Min(maxLat, math.Pi/2)
+		minLong, maxLong = -math.Pi, math.Pi
+	}
+
+	return &GeoPoint{Latitude: minLat * 180 / math.Pi, Longitude: minLong * 180 / math.Pi},
+		&GeoPoint{Latitude: maxLat * 180 / math.Pi, Longitude: maxLong * 180 / math.Pi}
+}
+
+// Geohash encodes the GeoPoint into geohash of precision characters
+func (point *GeoPoint) Geohash(precision int) string {
+	latRange := [2]float64{-90, 90}
+	longRange := [2]float64{-180, 180}
+
+	var builder strings.Builder
+	even := true
+	bit, index := 0, 0
+	for builder.Len() < precision {
+		if even {
+			mid := (longRange[0] + longRange[1]) / 2
+			if point.Longitude >= mid {
+				index = index<<1 | 1
+				longRange[0] = mid
+			} else {
+				index = index << 1
+				longRange[1] = mid
+			}
+		} else {
+			mid := (latRange[0] + latRange[1]) / 2
+			if point.Latitude >= mid {
+				index = index<<1 | 1
+				latRange[0] = mid
+			} else {
+				index = index << 1
+				latRange[1] = mid
+			}
+		}
+		even = !even
+
+		bit++
+		if bit == 5 {
+			builder.WriteByte(geohashBase32[index])
+			bit, index = 0, 0
+		}
+	}
+
+	return builder.String()
+}
+
+// DecodeGeohash returns the center of the cell of the geohash
+func DecodeGeohash(hash string) (*GeoPoint, error) {
+	if hash == "" {
+		return nil, fmt.Errorf("invalid geohash: empty")
+	}
+
+	latRange := [2]float64{-90, 90}
+	longRange := [2]float64{-180, 180}
+
+	even := true
+	for _, c := range strings.ToLower(hash) {
+		index := strings.IndexRune(geohashBase32, c)
+		if index < 0 {
+			return nil, fmt.Errorf("invalid geohash %s: unexpected character %c", hash, c)
+		}
+
+		for mask := 16; mask > 0; mask >>= 1 {
+			target := &latRange
+			if even {
+				target = &longRange
+			}
+			mid := (target[0] + target[1]) / 2
+			if index&mask != 0 {
+				target[0] = mid
+			} else {
+				target[1] = mid
+			}
+			even = !even
+		}
+	}
+
+	return &GeoPoint{
+		Latitude:  (latRange[0] + latRange[1]) / 2,
+		Longitude: (longRange[0] + longRange[1]) / 2,
+	}, nil
+}
+
+// MarshalJSON encodes the GeoPoint in the form of LeanCloud with __type
+func (point GeoPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(encodeGeoPoint(&point))
+}
+
+// UnmarshalJSON decodes the GeoPoint, with or without __type
+func (point *GeoPoint) UnmarshalJSON(data []byte) error {
+	var fields struct {
+		Type      string   `json:"__type"`
+		Latitude  *float64 `json:"latitude"`
+		Longitude *float64 `json:"longitude"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	if fields.Type != "" && fields.Type != "GeoPoint" {
+		return fmt.Errorf("unexpected __type %s of GeoPoint", fields.Type)
+	}
+
+	if fields.Latitude == nil || fields.Longitude == nil {
+		return fmt.Errorf("unexpected GeoPoint: latitude or longitude is missing")
+	}
+
+	point.Latitude = *fields.Latitude
+	point.Longitude = *fields.Longitude
+
+	return nil
 }
@@ -0,0 +1,104 @@
+package leancloud
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestGeoPointDistance(t *testing.T) {
+	beijing := &GeoPoint{Latitude: 39.9042, Longitude: 116.4074}
+	shanghai := &GeoPoint{Latitude: 31.2304, Longitude: 121.4737}
+
+	if distance := beijing.KilometersTo(shanghai); math.Abs(distance-1067.3) > 1 {
+		t.Fatalf("unexpected distance %v km", distance)
+	}
+
+	if distance := beijing.MilesTo(shanghai); math.Abs(distance-663.2) > 1 {
+		t.Fatalf("unexpected distance %v mi", distance)
+	}
+
+	if distance := beijing.RadiansTo(beijing); distance != 0 {
+		t.Fatalf("unexpected distance %v to itself", distance)
+	}
+}
+
+func TestGeoPointValidate(t *testing.T) {
+	if _, err := NewGeoPoint(39.9, 116.4); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, point := range []GeoPoint{{91, 0}, {-91, 0}, {0, 181}, {0, -181}, {math.NaN(), 0}} {
+		if err := point.Validate(); err == nil {
+			t.Errorf("invalid point %v accepted", point)
+		}
+	}
+}
+
+func TestGeoPointBoundingBox(t *testing.T) {
+	center := &GeoPoint{Latitude: 39.9, Longitude: 116.4}
+	southwest, northeast := center.BoundingBoxInKilometers(10)
+
+	for _, corner := range []*GeoPoint{
+		{Latitude: southwest.Latitude, Longitude: center.Longitude},
+		{Latitude: northeast.Latitude, Longitude: center.Longitude},
+	} {
+		if distance := center.KilometersTo(corner); math.Abs(distance-10) > 0.01 {
+			t.Fatalf("unexpected distance %v to edge of bounding box", distance)
+		}
+	}
+	if southwest.Longitude >= center.Longitude || northeast.Longitude <= center.Longitude {
+		t.Fatal("center out of bounding box")
+	}
+
+	southwest, northeast = (&GeoPoint{Latitude: 0, Longitude: 179.99}).BoundingBoxInKilometers(10)
+	if southwest.Longitude <= northeast.Longitude {
+		t.Fatal("bounding box should cross the antimeridian")
+	}
+
+	southwest, northeast = (&GeoPoint{Latitude: 89.99, Longitude: 0}).BoundingBoxInKilometers(10)
+	if northeast.Latitude != 90 || southwest.Longitude != -180 || northeast.Longitude != 180 {
+		t.Fatal("bounding box should include the pole")
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	point := &GeoPoint{Latitude: 57.64911, Longitude: 10.40744}
+	if hash := point.Geohash(11); hash != "u4pruydqqvj" {
+		t.Fatalf("unexpected geohash %s", hash)
+	}
+
+	decoded, err := DecodeGeohash("u4pruydqqvj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(decoded.Latitude-point.Latitude) > 1e-5 || math.Abs(decoded.Longitude-point.Longitude) > 1e-5 {
+		t.Fatalf("unexpected decoded point %v", decoded)
+	}
+
+	if _, err := DecodeGeohash("u4pa"); err == nil {
+		t.Fatal("invalid geohash accepted")
+	}
+}
+
+func TestGeoPointJSON(t *testing.T) {
+	data, err := json.Marshal(GeoPoint{Latitude: 39.9, Longitude: 116.4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"__type":"GeoPoint","latitude":39.9,"longitude":116.4}` {
+		t.Fatalf("unexpected JSON %s", string(data))
+	}
+
+	point := new(GeoPoint)
+	if err := json.Unmarshal(data, point); err != nil {
+		t.Fatal(err)
+	}
+	if point.Latitude != 39.9 || point.Longitude != 116.4 {
+		t.Fatalf("unexpected point %v", point)
+	}
+
+	if err := json.Unmarshal([]byte(`{"__type":"Date","iso":""}`), point); err == nil {
+		t.Fatal("unexpected __type accepted")
+	}
+}
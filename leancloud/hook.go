@@ -146,6 +146,9 @@ func OnLogin(fn func(*ClassHookRequest) error) {
 		if err != nil {
 			return nil, err
 		}
+		if client := engineClient(); client.sessionCache != nil {
+			client.sessionCache.RemoveUser(user.ID)
+		}
 		req := ClassHookRequest{
 			User: user,
 			Meta: r.Meta,
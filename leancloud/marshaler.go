@@ -0,0 +1,127 @@
+package leancloud
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// LeanCloudMarshaler is implemented by types which encode themselves into values stored in LeanCloud,
+// e.g. a string, a number or a map. Values returned are encoded again, so SDK types like time.Time could be used
+type LeanCloudMarshaler interface {
+	MarshalLeanCloud() (interface{}, error)
+}
+
+// LeanCloudUnmarshaler is implemented by types which decode themselves from values stored in LeanCloud,
+// value is decoded in the same way as fields of Object, e.g. *time.Time for Date and float64 for Number
+type LeanCloudUnmarshaler interface {
+	UnmarshalLeanCloud(value interface{}) error
+}
+
+var (
+	leanCloudMarshalerType   = reflect.TypeOf((*LeanCloudMarshaler)(nil)).Elem()
+	leanCloudUnmarshalerType = reflect.TypeOf((*LeanCloudUnmarshaler)(nil)).Elem()
+	textMarshalerType        = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType      = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// encodeError keeps errors of marshalers in encoded values, which fails the request when marshalled into JSON
+type encodeError struct {
+	err error
+}
+
+func (err encodeError) MarshalJSON() ([]byte, error) {
+	return nil, err.err
+}
+
+// embedsObject reports whether the type is a struct embedding Object, or User which embeds Object,
+// such structs are always encoded and bound as objects even if they pick up marshalers from other embedded fields
+func embedsObject(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	field, ok := t.FieldByName("Object")
+	return ok && field.Anonymous && field.Type == reflect.TypeOf(Object{})
+}
+
+// encodeMarshaler encodes values implementing LeanCloudMarshaler or encoding.TextMarshaler,
+// either by value or by pointer, returns false if the value implements neither of them or embeds Object
+func encodeMarshaler(object interface{}, ignoreZero bool) (interface{}, bool) {
+	v := reflect.ValueOf(object)
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) || embedsObject(v.Type()) {
+		return nil, false
+	}
+
+	if !v.Type().Implements(leanCloudMarshalerType) && !v.Type().Implements(textMarshalerType) && v.Kind() != reflect.Ptr {
+		// methods with pointer receivers
+		pv := reflect.New(v.Type())
+		pv.Elem().Set(v)
+		v = pv
+	}
+
+	switch marshaler := v.Interface().(type) {
+	case LeanCloudMarshaler:
+		value, err := marshaler.MarshalLeanCloud()
+		if err != nil {
+			return encodeError{fmt.Errorf("unable to encode %v: %w", reflect.TypeOf(object), err)}, true
+		}
+		return encode(value, ignoreZero), true
+	case encoding.TextMarshaler:
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return encodeError{fmt.Errorf("unable to encode %v: %w", reflect.TypeOf(object), err)}, true
+		}
+		return string(text), true
+	}
+
+	return nil, false
+}
+
+// bindUnmarshaler binds src into dst implementing LeanCloudUnmarshaler or encoding.TextUnmarshaler by pointer,
+// returns false if dst implements neither of them or embeds Object. encoding.TextUnmarshaler is used only if src is a string
+func bindUnmarshaler(src reflect.Value, dst reflect.Value) (bool, error) {
+	for src.IsValid() && src.Kind() == reflect.Interface {
+		src = src.Elem()
+	}
+	if !src.IsValid() || embedsObject(dst.Type()) {
+		return false, nil
+	}
+
+	var target reflect.Value
+	if dst.Kind() == reflect.Ptr && (dst.Type().Implements(leanCloudUnmarshalerType) || dst.Type().Implements(textUnmarshalerType)) {
+		target = dst
+	} else if dst.CanAddr() && (dst.Addr().Type().Implements(leanCloudUnmarshalerType) || dst.Addr().Type().Implements(textUnmarshalerType)) {
+		target = dst.Addr()
+	} else {
+		return false, nil
+	}
+
+	_, isUnmarshaler := target.Interface().(LeanCloudUnmarshaler)
+	if !isUnmarshaler && src.Kind() != reflect.String {
+		return false, nil
+	}
+
+	if target.IsNil() {
+		if !target.CanSet() {
+			return false, nil
+		}
+		target.Set(reflect.New(target.Type().Elem()))
+	}
+
+	switch unmarshaler := target.Interface().(type) {
+	case LeanCloudUnmarshaler:
+		if err := unmarshaler.UnmarshalLeanCloud(src.Interface()); err != nil {
+			return true, fmt.Errorf("unable to bind %v: %w", dst.Type(), err)
+		}
+	case encoding.TextUnmarshaler:
+		if err := unmarshaler.UnmarshalText([]byte(src.String())); err != nil {
+			return true, fmt.Errorf("unable to bind %v: %w", dst.Type(), err)
+		}
+	}
+
+	return true, nil
+}
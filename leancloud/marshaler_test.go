@@ -0,0 +1,138 @@
+package leancloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testMoney struct {
+	cents int64
+}
+
+func (money testMoney) MarshalLeanCloud() (interface{}, error) {
+	if money.cents < 0 {
+		return nil, fmt.Errorf("negative amount")
+	}
+	return money.cents, nil
+}
+
+func (money *testMoney) UnmarshalLeanCloud(value interface{}) error {
+	cents, ok := value.(float64)
+	if !ok {
+		return fmt.Errorf("want number but %v", reflect.TypeOf(value))
+	}
+	money.cents = int64(cents)
+	return nil
+}
+
+type testLevel int
+
+func (level *testLevel) MarshalText() ([]byte, error) {
+	return []byte([]string{"low", "high"}[*level]), nil
+}
+
+func (level *testLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*level = 0
+	case "high":
+		*level = 1
+	default:
+		return fmt.Errorf("unknown level %s", string(text))
+	}
+	return nil
+}
+
+type testOrder struct {
+	Object
+	Price    testMoney  `json:"price"`
+	Discount *testMoney `json:"discount"`
+	Level    testLevel  `json:"level"`
+}
+
+func TestMarshalerEncode(t *testing.T) {
+	order := testOrder{
+		Price:    testMoney{1999},
+		Discount: &testMoney{100},
+		Level:    1,
+	}
+
+	encoded, err := json.Marshal(encodeObject(&order, false, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `{"discount":100,"level":"high","price":1999}` {
+		t.Fatalf("unexpected encoded %s", string(encoded))
+	}
+
+	order.Price = testMoney{-1}
+	if _, err := json.Marshal(encodeObject(&order, false, false)); err == nil || !strings.Contains(err.Error(), "negative amount") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestMarshalerBind(t *testing.T) {
+	fields := map[string]interface{}{
+		"price":    float64(1999),
+		"discount": float64(100),
+		"level":    "high",
+	}
+
+	order := new(testOrder)
+	if err := bind(reflect.ValueOf(fields), reflect.ValueOf(order).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if order.Price.cents != 1999 || order.Discount == nil || order.Discount.cents != 100 || order.Level != 1 {
+		t.Fatalf("dismatch order %v", order)
+	}
+
+	fields["level"] = "unknown"
+	if err := bind(reflect.ValueOf(fields), reflect.ValueOf(new(testOrder)).Elem()); err == nil {
+		t.Fatal("invalid level accepted")
+	}
+}
+
+func TestMarshalerEmbeddedInObject(t *testing.T) {
+	// marshalers promoted from other embedded fields should not take over structs embedding Object
+	type taggedOrder struct {
+		Object
+		testLevel
+		Price testMoney `json:"price"`
+	}
+
+	order := taggedOrder{Price: testMoney{1999}}
+	if encoded := encode(&order, false); reflect.TypeOf(encoded) != reflect.TypeOf(map[string]interface{}{}) {
+		t.Fatalf("unexpected encoded %v", encoded)
+	}
+
+	type pricedOrder struct {
+		Object
+		testMoney
+		Level testLevel `json:"level"`
+	}
+
+	bound := new(pricedOrder)
+	if err := bind(reflect.ValueOf(map[string]interface{}{"level": "high"}), reflect.ValueOf(bound).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if bound.Level != 1 {
+		t.Fatalf("dismatch order %v", bound)
+	}
+
+	schema := &ClassSchema{
+		ClassName: "Order",
+		Fields: map[string]*SchemaField{
+			"order": {Type: "String"},
+		},
+	}
+	type orderRef struct {
+		Object
+		Order taggedOrder `json:"order"`
+	}
+	if mismatches := CheckSchema(schema, orderRef{}); len(mismatches) != 1 {
+		t.Fatalf("unexpected mismatches %v", mismatches)
+	}
+}
@@ -113,6 +113,29 @@ func (object *Object) ACL() *ACL {
 	return aclPtr
 }
 
+// Highlights returns highlighted fragments by field of the Object found by SearchQuery
+func (object *Object) Highlights() map[string][]string {
+	highlight, ok := object.fields["_highlight"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	highlights := make(map[string][]string)
+	for key, value := range highlight {
+		fragments, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, fragment := range fragments {
+			if fragmentString, ok := fragment.(string); ok {
+				highlights[key] = append(highlights[key], fragmentString)
+			}
+		}
+	}
+
+	return highlights
+}
+
 // IsPointer shows whether the Object is a Pointer
 func (object *Object) IsPointer() bool {
 	return object.isPointer
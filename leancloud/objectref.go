@@ -10,9 +10,17 @@ import (
 )
 
 type ObjectRef struct {
-	c     *Client
-	class string
-	ID    string
+	c          *Client
+	class      string
+	ID         string
+	includeACL bool
+}
+
+// IncludeACL returns a copy of the reference which fetches the object with its ACL
+func (ref *ObjectRef) IncludeACL() *ObjectRef {
+	newRef := *ref
+	newRef.includeACL = true
+	return &newRef
 }
 
 func (client *Client) Object(object interface{}) *ObjectRef {
@@ -178,10 +186,12 @@ func objectGet(ref interface{}, object interface{}, authOptions ...AuthOption) e
 	path := "/1.1/"
 	var c *Client
 
+	includeACL := false
 	switch v := ref.(type) {
 	case *ObjectRef:
 		path = fmt.Sprint(path, "classes/", v.class, "/", v.ID)
 		c = v.c
+		includeACL = v.includeACL
 		break
 	case *UserRef:
 		path = fmt.Sprint(path, "users/", v.ID)
@@ -192,7 +202,14 @@ func objectGet(ref interface{}, object interface{}, authOptions ...AuthOption) e
 		c = v.c
 	}
 
-	resp, err := c.request(methodGet, path, c.getRequestOptions(), authOptions...)
+	options := c.getRequestOptions()
+	if includeACL {
+		options.Params = map[string]string{
+			"returnACL": "true",
+		}
+	}
+
+	resp, err := c.request(methodGet, path, options, authOptions...)
 	if err != nil {
 		return err
 	}
@@ -236,8 +253,15 @@ func objectGet(ref interface{}, object interface{}, authOptions ...AuthOption) e
 		if err != nil {
 			return err
 		}
+		if decodedFile == nil {
+			return fmt.Errorf("unexpected error when parse File from response: objectId is missing")
+		}
 		decodedFile.ref = v
-		object = decodedFile
+		filePtr, ok := object.(*File)
+		if !ok {
+			return fmt.Errorf("file should be *File but %v", reflect.TypeOf(object))
+		}
+		*filePtr = *decodedFile
 	}
 
 	return nil
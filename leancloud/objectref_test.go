@@ -1,6 +1,8 @@
 package leancloud
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -262,3 +264,69 @@ func TestObjectDestroy(t *testing.T) {
 		}
 	}
 }
+
+func TestObjectRefGetWithACL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("returnACL") != "true" {
+			w.Write([]byte(`{"objectId":"staff1","createdAt":"2020-01-01T00:00:00.000Z","updatedAt":"2020-01-01T00:00:00.000Z","name":"Tom"}`))
+			return
+		}
+		w.Write([]byte(`{"objectId":"staff1","createdAt":"2020-01-01T00:00:00.000Z","updatedAt":"2020-01-01T00:00:00.000Z","name":"Tom","ACL":{"*":{"read":true},"role:admin":{"write":true}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		AppID:     "app",
+		AppKey:    "key",
+		ServerURL: server.URL,
+	})
+
+	object := new(Object)
+	if err := client.Class("Staff").ID("staff1").Get(object); err != nil {
+		t.Fatal(err)
+	}
+	if object.ACL() != nil {
+		t.Fatal("unexpected ACL")
+	}
+
+	if err := client.Class("Staff").ID("staff1").IncludeACL().Get(object); err != nil {
+		t.Fatal(err)
+	}
+	if acl := object.ACL(); acl == nil || !acl.GetPublicReadAccess() {
+		t.Fatal("ACL not returned")
+	}
+
+	staff := new(struct {
+		Object
+		Name string `json:"name"`
+		ACL  *ACL   `json:"ACL"`
+	})
+	if err := client.Class("Staff").ID("staff1").IncludeACL().Get(staff); err != nil {
+		t.Fatal(err)
+	}
+	if staff.ACL == nil || !staff.ACL.GetRoleWriteAccessByName("admin") || staff.Name != "Tom" {
+		t.Fatal("ACL not bound")
+	}
+
+	staffWithACL := new(struct {
+		Object
+		ACL ACL `json:"ACL"`
+	})
+	if err := client.Class("Staff").ID("staff1").IncludeACL().Get(staffWithACL); err != nil {
+		t.Fatal(err)
+	}
+	if !staffWithACL.ACL.GetPublicReadAccess() {
+		t.Fatal("ACL not bound")
+	}
+}
+
+func TestQueryIncludeACL(t *testing.T) {
+	params, err := wrapParams(c.Class("Staff").NewQuery().IncludeACL(), false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["returnACL"] != "true" {
+		t.Fatal("returnACL not sent")
+	}
+}
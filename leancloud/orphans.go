@@ -0,0 +1,212 @@
+package leancloud
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOrphanBatchSize    = 100
+	defaultDestroyConcurrency = 4
+)
+
+// FileReference names a field which refers to files, holding either File or Pointer to _File,
+// or arrays of them
+type FileReference struct {
+	Class string
+	Field string
+}
+
+// OrphanFileOptions describes where files are referred and how orphans are cleaned up
+type OrphanFileOptions struct {
+	References []FileReference
+
+	// CreatedBefore excludes files created after it, which may not be referred yet. Zero means no limit
+	CreatedBefore time.Time
+
+	// BatchSize is the number of rows fetched per query, 100 by default
+	BatchSize int
+
+	// Concurrency is the number of files destroyed at a time, 4 by default
+	Concurrency int
+
+	// DryRun reports orphans without destroying them
+	DryRun bool
+}
+
+// OrphanFileReport is the result of Files.DestroyOrphans
+type OrphanFileReport struct {
+	Scanned   int
+	Orphans   []*File
+	Destroyed []*File
+	Errors    map[string]error
+}
+
+func (options *OrphanFileOptions) batchSize() int {
+	if options == nil || options.BatchSize <= 0 {
+		return defaultOrphanBatchSize
+	}
+
+	return options.BatchSize
+}
+
+// FindOrphans returns files which are not referred by any of options.References
+func (ref *Files) FindOrphans(options *OrphanFileOptions, authOptions ...AuthOption) ([]*File, error) {
+	orphans, _, err := ref.findOrphans(options, authOptions...)
+	return orphans, err
+}
+
+// DestroyOrphans finds files which are not referred by any of options.References and destroys them,
+// unless options.DryRun is set
+func (ref *Files) DestroyOrphans(options *OrphanFileOptions, authOptions ...AuthOption) (*OrphanFileReport, error) {
+	orphans, scanned, err := ref.findOrphans(options, authOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &OrphanFileReport{
+		Scanned: scanned,
+		Orphans: orphans,
+		Errors:  make(map[string]error),
+	}
+	if options.DryRun {
+		return report, nil
+	}
+
+	report.Destroyed, report.Errors = ref.DestroyAll(orphans, options.Concurrency, authOptions...)
+
+	return report, nil
+}
+
+// DestroyAll destroys files by concurrency workers, 4 by default, returning destroyed files and errors by ID of files failed
+func (ref *Files) DestroyAll(files []*File, concurrency int, authOptions ...AuthOption) ([]*File, map[string]error) {
+	if concurrency <= 0 {
+		concurrency = defaultDestroyConcurrency
+	}
+
+	results := make([]error, len(files))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency && worker < len(files); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = ref.Destroy(files[i], authOptions...)
+			}
+		}()
+	}
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var destroyed []*File
+	errs := make(map[string]error)
+	for i, file := range files {
+		if results[i] != nil {
+			errs[file.ID] = results[i]
+		} else {
+			destroyed = append(destroyed, file)
+		}
+	}
+
+	return destroyed, errs
+}
+
+func (ref *Files) findOrphans(options *OrphanFileOptions, authOptions ...AuthOption) ([]*File, int, error) {
+	if options == nil || len(options.References) == 0 {
+		return nil, 0, fmt.Errorf("unable to find orphan files: references are missing")
+	}
+
+	referred := make(map[string]bool)
+	for _, reference := range options.References {
+		if err := ref.collectReferences(reference, options.batchSize(), referred, authOptions...); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var orphans []*File
+	scanned := 0
+	lastID := ""
+	for {
+		query := ref.NewQuery().Order("objectId").Limit(options.batchSize())
+		if lastID != "" {
+			query.GreaterThan("objectId", lastID)
+		}
+		if !options.CreatedBefore.IsZero() {
+			query.LessThan("createdAt", options.CreatedBefore)
+		}
+
+		var files []*File
+		if err := query.Find(&files, authOptions...); err != nil {
+			return nil, 0, err
+		}
+
+		for _, file := range files {
+			if !referred[file.ID] {
+				orphans = append(orphans, file)
+			}
+		}
+		scanned += len(files)
+
+		if len(files) < options.batchSize() {
+			break
+		}
+		lastID = files[len(files)-1].ID
+	}
+
+	return orphans, scanned, nil
+}
+
+func (ref *Files) collectReferences(reference FileReference, batchSize int, referred map[string]bool, authOptions ...AuthOption) error {
+	lastID := ""
+	for {
+		query := ref.c.Class(reference.Class).NewQuery().Exists(reference.Field).Select(reference.Field).Order("objectId").Limit(batchSize)
+		if lastID != "" {
+			query.GreaterThan("objectId", lastID)
+		}
+
+		var objects []Object
+		if err := query.Find(&objects, authOptions...); err != nil {
+			return fmt.Errorf("unable to collect files referred by %s.%s: %w", reference.Class, reference.Field, err)
+		}
+
+		for i := range objects {
+			collectFileIDs(objects[i].Get(reference.Field), referred)
+		}
+
+		if len(objects) < batchSize {
+			return nil
+		}
+		lastID = objects[len(objects)-1].ID
+	}
+}
+
+// collectFileIDs adds IDs of File and Pointer to _File found in the decoded value to ids
+func collectFileIDs(value interface{}, ids map[string]bool) {
+	switch v := value.(type) {
+	case *File:
+		if v != nil && v.ID != "" {
+			ids[v.ID] = true
+		}
+	case File:
+		collectFileIDs(&v, ids)
+	case *Object:
+		if v != nil && v.isPointer && v.fields["className"] == "_File" {
+			ids[v.ID] = true
+		}
+	case Object:
+		collectFileIDs(&v, ids)
+	case []interface{}:
+		for _, item := range v {
+			collectFileIDs(item, ids)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			collectFileIDs(item, ids)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package leancloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCollectFileIDs(t *testing.T) {
+	value := []interface{}{
+		&File{Object: Object{ID: "file1"}},
+		&Object{ID: "file2", isPointer: true, fields: map[string]interface{}{"className": "_File"}},
+		&Object{ID: "user1", isPointer: true, fields: map[string]interface{}{"className": "_User"}},
+		map[string]interface{}{
+			"cover": &File{Object: Object{ID: "file3"}},
+		},
+		"file4",
+	}
+
+	ids := make(map[string]bool)
+	collectFileIDs(value, ids)
+
+	if len(ids) != 3 || !ids["file1"] || !ids["file2"] || !ids["file3"] {
+		t.Fatalf("dismatch file IDs: %v", ids)
+	}
+}
+
+// orphanServer serves posts referring files, and files of which those failing to be deleted are given
+type orphanServer struct {
+	*httptest.Server
+	mutex     sync.Mutex
+	deleted   []string
+	inFlight  int
+	maxFlight int
+}
+
+func newOrphanServer(t *testing.T, failures map[string]bool) *orphanServer {
+	posts := []string{
+		`{"objectId":"post1","createdAt":"2020-08-31T03:01:30.654Z","updatedAt":"2020-08-31T03:01:30.654Z","cover":{"__type":"File","objectId":"file1","url":"https://example.com/1"}}`,
+		`{"objectId":"post2","createdAt":"2020-08-31T03:01:30.654Z","updatedAt":"2020-08-31T03:01:30.654Z","cover":{"__type":"Pointer","className":"_File","objectId":"file2"}}`,
+		`{"objectId":"post3","createdAt":"2020-08-31T03:01:30.654Z","updatedAt":"2020-08-31T03:01:30.654Z","cover":[{"__type":"File","objectId":"file3","url":"https://example.com/3"}]}`,
+	}
+	var files []string
+	for i := 1; i <= 8; i++ {
+		files = append(files, fmt.Sprintf(`{"objectId":"file%d","name":"%d.txt","url":"https://example.com/%d","createdAt":"2020-08-31T03:01:30.654Z"}`, i, i, i))
+	}
+
+	server := new(orphanServer)
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/1.1/classes/Post":
+			if r.URL.Query().Get("keys") != "cover" || r.URL.Query().Get("order") != "objectId" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprintf(w, `{"results":[%s]}`, strings.Join(pageRows(t, r, posts), ","))
+		case r.Method == "GET" && r.URL.Path == "/1.1/classes/files":
+			fmt.Fprintf(w, `{"results":[%s]}`, strings.Join(pageRows(t, r, files), ","))
+		case r.Method == "DELETE" && strings.HasPrefix(r.URL.Path, "/1.1/files/"):
+			id := strings.TrimPrefix(r.URL.Path, "/1.1/files/")
+			server.mutex.Lock()
+			server.inFlight++
+			if server.inFlight > server.maxFlight {
+				server.maxFlight = server.inFlight
+			}
+			server.mutex.Unlock()
+			defer func() {
+				server.mutex.Lock()
+				server.inFlight--
+				server.mutex.Unlock()
+			}()
+
+			if failures[id] {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"code":1,"error":"internal error"}`))
+				return
+			}
+			server.mutex.Lock()
+			server.deleted = append(server.deleted, id)
+			server.mutex.Unlock()
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":404,"error":"not found"}`))
+		}
+	}))
+
+	return server
+}
+
+// pageRows returns rows ordered by objectId after where.objectId.$gt, limited by limit
+func pageRows(t *testing.T, r *http.Request, rows []string) []string {
+	where := make(map[string]map[string]interface{})
+	if err := json.Unmarshal([]byte(r.URL.Query().Get("where")), &where); err != nil {
+		t.Errorf("unexpected where %s", r.URL.Query().Get("where"))
+	}
+	after, _ := where["objectId"]["$gt"].(string)
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	var page []string
+	for _, row := range rows {
+		fields := make(map[string]interface{})
+		json.Unmarshal([]byte(row), &fields)
+		if fields["objectId"].(string) > after && len(page) < limit {
+			page = append(page, row)
+		}
+	}
+
+	return page
+}
+
+func fileIDs(files []*File) []string {
+	ids := make([]string, len(files))
+	for i, file := range files {
+		ids[i] = file.ID
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func TestFindOrphans(t *testing.T) {
+	server := newOrphanServer(t, nil)
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{AppID: "app", AppKey: "key", ServerURL: server.URL})
+	options := &OrphanFileOptions{
+		References: []FileReference{{Class: "Post", Field: "cover"}},
+		BatchSize:  2,
+	}
+
+	orphans, err := client.Files.FindOrphans(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := fileIDs(orphans); !reflect.DeepEqual(ids, []string{"file4", "file5", "file6", "file7", "file8"}) {
+		t.Fatalf("dismatch orphans %v", ids)
+	}
+
+	options.DryRun = true
+	report, err := client.Files.DestroyOrphans(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Scanned != 8 || len(report.Orphans) != 5 || len(report.Destroyed) != 0 || len(server.deleted) != 0 {
+		t.Fatalf("unexpected report of dry run %v", report)
+	}
+
+	if _, err := client.Files.FindOrphans(&OrphanFileOptions{}); err == nil {
+		t.Fatal("orphans found without references")
+	}
+}
+
+func TestDestroyOrphans(t *testing.T) {
+	server := newOrphanServer(t, map[string]bool{"file6": true})
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{AppID: "app", AppKey: "key", ServerURL: server.URL})
+	report, err := client.Files.DestroyOrphans(&OrphanFileOptions{
+		References:  []FileReference{{Class: "Post", Field: "cover"}},
+		BatchSize:   3,
+		Concurrency: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := fileIDs(report.Destroyed); !reflect.DeepEqual(ids, []string{"file4", "file5", "file7", "file8"}) {
+		t.Fatalf("dismatch destroyed files %v", ids)
+	}
+	if len(report.Errors) != 1 || report.Errors["file6"] == nil {
+		t.Fatalf("dismatch errors %v", report.Errors)
+	}
+	if server.maxFlight > 2 {
+		t.Fatalf("%d files destroyed at a time", server.maxFlight)
+	}
+}
@@ -0,0 +1,195 @@
+package leancloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// qiniuQueryURL is the API querying hosts of the bucket's region, a variable to be replaced in tests
+var qiniuQueryURL = "https://uc.qbox.me/v2/query"
+
+// qiniuQueryTimeout bounds the region query, which otherwise hangs uploads without deadline if Qiniu is unreachable
+var qiniuQueryTimeout = 10 * time.Second
+
+var qiniuDefaultUploadHosts = []string{
+	"https://upload.qiniup.com",
+	"https://up.qiniup.com",
+	"https://up.qbox.me",
+}
+
+type qiniuRegionHosts struct {
+	Main   []string `json:"main"`
+	Backup []string `json:"backup"`
+}
+
+type qiniuQueryResponse struct {
+	TTL int `json:"ttl"`
+	Up  struct {
+		Acc qiniuRegionHosts `json:"acc"`
+		Src qiniuRegionHosts `json:"src"`
+	} `json:"up"`
+}
+
+type qiniuRegionCacheEntry struct {
+	hosts     []string
+	expiresAt time.Time
+}
+
+var qiniuRegionCache = make(map[string]qiniuRegionCacheEntry)
+
+var qiniuRegionCacheMutex sync.Mutex
+
+// qiniuUploadHosts lists upload hosts to be tried in order: the upload_url issued with the token,
+// then hosts of the bucket's region queried from Qiniu and the default hosts, which are resolved
+// only if upload_url is missing or unreachable
+type qiniuUploadHosts struct {
+	token    string
+	bucket   string
+	hosts    []string
+	resolved bool
+}
+
+func newQiniuUploadHosts(token, uploadURL, bucket string) *qiniuUploadHosts {
+	hosts := &qiniuUploadHosts{
+		token:  token,
+		bucket: bucket,
+	}
+	hosts.add(uploadURL)
+
+	return hosts
+}
+
+// get returns the i-th host, and false if there is no more host
+func (hosts *qiniuUploadHosts) get(ctx context.Context, i int) (string, bool) {
+	if i >= len(hosts.hosts) && !hosts.resolved {
+		hosts.resolved = true
+		if regionHosts, err := queryQiniuRegionHosts(ctx, hosts.token, hosts.bucket); err == nil {
+			hosts.add(regionHosts...)
+		}
+		hosts.add(qiniuDefaultUploadHosts...)
+	}
+
+	if i >= len(hosts.hosts) {
+		return "", false
+	}
+
+	return hosts.hosts[i], true
+}
+
+func (hosts *qiniuUploadHosts) add(newHosts ...string) {
+	for _, host := range newHosts {
+		host = strings.TrimSuffix(host, "/")
+		if host == "" {
+			continue
+		}
+		seen := false
+		for _, existing := range hosts.hosts {
+			seen = seen || existing == host
+		}
+		if !seen {
+			hosts.hosts = append(hosts.hosts, host)
+		}
+	}
+}
+
+func queryQiniuRegionHosts(ctx context.Context, token, bucket string) ([]string, error) {
+	accessKey := strings.SplitN(token, ":", 2)[0]
+	if accessKey == "" || bucket == "" {
+		return nil, fmt.Errorf("unable to query region of Qiniu bucket: access key or bucket is missing")
+	}
+
+	cacheKey := fmt.Sprint(accessKey, ":", bucket)
+	qiniuRegionCacheMutex.Lock()
+	entry, ok := qiniuRegionCache[cacheKey]
+	qiniuRegionCacheMutex.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.hosts, nil
+	}
+
+	query := url.Values{
+		"ak":     {accessKey},
+		"bucket": {bucket},
+	}
+	ctx, cancel := context.WithTimeout(ctx, qiniuQueryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprint(qiniuQueryURL, "?", query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error when query region of Qiniu bucket: %v", err)
+	}
+	defer resp.Body.Close()
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error when query region of Qiniu bucket: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected error when query region of Qiniu bucket: %v", string(content))
+	}
+
+	respJSON := new(qiniuQueryResponse)
+	if err := json.Unmarshal(content, respJSON); err != nil {
+		return nil, fmt.Errorf("unexpected error when query region of Qiniu bucket: %v", err)
+	}
+
+	var hosts []string
+	for _, group := range [][]string{respJSON.Up.Acc.Main, respJSON.Up.Acc.Backup, respJSON.Up.Src.Main, respJSON.Up.Src.Backup} {
+		for _, host := range group {
+			if !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
+				host = fmt.Sprint("https://", host)
+			}
+			hosts = append(hosts, host)
+		}
+	}
+
+	ttl := time.Duration(respJSON.TTL) * time.Second
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+	qiniuRegionCacheMutex.Lock()
+	qiniuRegionCache[cacheKey] = qiniuRegionCacheEntry{
+		hosts:     hosts,
+		expiresAt: time.Now().Add(ttl),
+	}
+	qiniuRegionCacheMutex.Unlock()
+
+	return hosts, nil
+}
+
+// isConnectionError reports whether the request failed before reaching the host, so it is safe to try another one
+func isConnectionError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Op == "dial"
+	}
+
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// countingReader counts bytes read, to tell whether the reader could still be sent to another host
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (reader *countingReader) Read(p []byte) (int, error) {
+	n, err := reader.reader.Read(p)
+	reader.count += int64(n)
+	return n, err
+}
@@ -0,0 +1,211 @@
+package leancloud
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// listQiniuUploadHosts resolves all upload hosts to be tried
+func listQiniuUploadHosts(token, uploadURL, bucket string) []string {
+	hosts := newQiniuUploadHosts(token, uploadURL, bucket)
+	var list []string
+	for i := 0; ; i++ {
+		host, ok := hosts.get(context.Background(), i)
+		if !ok {
+			return list
+		}
+		list = append(list, host)
+	}
+}
+
+func TestQiniuUploadHosts(t *testing.T) {
+	hosts := listQiniuUploadHosts("", "https://up.qbox.me/", "")
+	if !reflect.DeepEqual(hosts, []string{"https://up.qbox.me", "https://upload.qiniup.com", "https://up.qiniup.com"}) {
+		t.Fatalf("unexpected hosts: %v", hosts)
+	}
+}
+
+// useQiniuQueryServer points queries of regions to a server responding with hosts, until the test finishes
+func useQiniuQueryServer(t *testing.T, hosts ...string) *int {
+	resetQiniuRegionCache()
+	queries := new(int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*queries++
+		if r.URL.Query().Get("ak") != "access" || r.URL.Query().Get("bucket") == "" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"ttl":86400,"up":{"acc":{"main":["%s"]},"src":{"main":["%s"],"backup":["up-backup.example.com"]}}}`, hosts[0], hosts[1])
+	}))
+
+	previous := qiniuQueryURL
+	qiniuQueryURL = fmt.Sprint(server.URL, "/v2/query")
+	t.Cleanup(func() {
+		qiniuQueryURL = previous
+		resetQiniuRegionCache()
+		server.Close()
+	})
+
+	return queries
+}
+
+func resetQiniuRegionCache() {
+	qiniuRegionCacheMutex.Lock()
+	defer qiniuRegionCacheMutex.Unlock()
+
+	qiniuRegionCache = make(map[string]qiniuRegionCacheEntry)
+}
+
+// closedURL returns the URL of a port refusing connections
+func closedURL(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener.Close()
+
+	return fmt.Sprint("http://", listener.Addr().String())
+}
+
+func TestQiniuRegionHosts(t *testing.T) {
+	queries := useQiniuQueryServer(t, "up-acc.example.com", "http://up-src.example.com")
+
+	for i := 0; i < 2; i++ {
+		hosts := listQiniuUploadHosts("access:sign:policy", "https://upload.example.com", "region-bucket")
+		wantHosts := append([]string{
+			"https://upload.example.com",
+			"https://up-acc.example.com",
+			"http://up-src.example.com",
+			"https://up-backup.example.com",
+		}, qiniuDefaultUploadHosts...)
+		if !reflect.DeepEqual(hosts, wantHosts) {
+			t.Fatalf("unexpected hosts: %v", hosts)
+		}
+	}
+
+	if *queries != 1 {
+		t.Fatalf("region queried %d times, want cached", *queries)
+	}
+}
+
+func TestQiniuRegionQueryTimeout(t *testing.T) {
+	resetQiniuRegionCache()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	previousURL, previousTimeout := qiniuQueryURL, qiniuQueryTimeout
+	qiniuQueryURL, qiniuQueryTimeout = fmt.Sprint(server.URL, "/v2/query"), 50*time.Millisecond
+	defer func() {
+		qiniuQueryURL, qiniuQueryTimeout = previousURL, previousTimeout
+	}()
+
+	start := time.Now()
+	hosts := listQiniuUploadHosts("access:sign:policy", "", "hanging-bucket")
+	if !reflect.DeepEqual(hosts, qiniuDefaultUploadHosts) {
+		t.Fatalf("unexpected hosts: %v", hosts)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("region query took %v", elapsed)
+	}
+}
+
+func TestQiniuUploadFallback(t *testing.T) {
+	content := []byte("temporary file's content")
+
+	t.Run("UploadURL", func(t *testing.T) {
+		var uploads int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			uploads++
+		}))
+		defer server.Close()
+		queries := useQiniuQueryServer(t, server.URL, server.URL)
+
+		err := new(qiniuStorageProvider).Upload(context.Background(), &StorageUpload{
+			File:      &File{Key: "key", Name: "test.txt", Bucket: "upload-url-bucket"},
+			Token:     "access:sign:policy",
+			UploadURL: server.URL,
+			Reader:    bytes.NewReader(content),
+			Size:      int64(len(content)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uploads != 1 || *queries != 0 {
+			t.Fatalf("uploaded %d times with %d region queries, want upload_url only", uploads, *queries)
+		}
+	})
+
+	t.Run("ConnectionError", func(t *testing.T) {
+		var received []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			f, _, err := r.FormFile("file")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			received, _ = ioutil.ReadAll(f)
+		}))
+		defer server.Close()
+		useQiniuQueryServer(t, server.URL, server.URL)
+
+		file := &File{Key: "key", Name: "test.txt", Bucket: "fallback-bucket"}
+		err := new(qiniuStorageProvider).Upload(context.Background(), &StorageUpload{
+			File:      file,
+			Token:     "access:sign:policy",
+			UploadURL: closedURL(t),
+			Reader:    bytes.NewReader(content),
+			Size:      int64(len(content)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(received, content) {
+			t.Fatal("dismatch content")
+		}
+	})
+
+	t.Run("PartlyRead", func(t *testing.T) {
+		var fallbacks int
+		fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fallbacks++
+		}))
+		defer fallback.Close()
+
+		// the first host drops the connection after reading a part of the file
+		broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body.Read(make([]byte, 16))
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+		}))
+		defer broken.Close()
+		useQiniuQueryServer(t, fallback.URL, fallback.URL)
+
+		err := new(qiniuStorageProvider).Upload(context.Background(), &StorageUpload{
+			File:      &File{Key: "key", Name: "test.txt", Bucket: "partly-read-bucket"},
+			Token:     "access:sign:policy",
+			UploadURL: broken.URL,
+			Reader:    bytes.NewReader(content),
+			Size:      int64(len(content)),
+		})
+		if err == nil {
+			t.Fatal("upload succeeded with broken connection")
+		}
+		if fallbacks != 0 {
+			t.Fatal("partly read file sent to another host")
+		}
+	})
+}
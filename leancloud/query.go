@@ -91,28 +91,46 @@ func (q *Query) And(queries ...*Query) *Query {
 	return q
 }
 
+// Near sorts results by distance to the point, the nearest first
 func (q *Query) Near(key string, point *GeoPoint) *Query {
 	q.where[key] = wrapCondition("$nearSphere", point, "")
 	return q
 }
 
+// WithinGeoBox matches GeoPoints in the rectangle from southwest to northeast
 func (q *Query) WithinGeoBox(key string, southwest *GeoPoint, northeast *GeoPoint) *Query {
 	q.where[key] = wrapCondition("$withinBox", []GeoPoint{*southwest, *northeast}, "")
 	return q
 }
 
-func (q *Query) WithinKilometers(key string, point *GeoPoint) *Query {
-	q.where[key] = wrapCondition("$maxDistanceInKilometers", point, "")
+// WithinKilometers matches GeoPoints within distance kilometers of the point, sorted by distance
+func (q *Query) WithinKilometers(key string, point *GeoPoint, distance float64) *Query {
+	q.where[key] = wrapCondition("$maxDistanceInKilometers", &geoDistance{point, distance}, "")
 	return q
 }
 
-func (q *Query) WithinMiles(key string, point *GeoPoint) *Query {
-	q.where[key] = wrapCondition("$maxDistanceInMiles", point, "")
+// WithinMiles matches GeoPoints within distance miles of the point, sorted by distance
+func (q *Query) WithinMiles(key string, point *GeoPoint, distance float64) *Query {
+	q.where[key] = wrapCondition("$maxDistanceInMiles", &geoDistance{point, distance}, "")
 	return q
 }
 
-func (q *Query) WithinRadians(key string, point *GeoPoint) *Query {
-	q.where[key] = wrapCondition("$maxDistanceInRadians", point, "")
+// WithinRadians matches GeoPoints within distance radians of the point, sorted by distance
+func (q *Query) WithinRadians(key string, point *GeoPoint, distance float64) *Query {
+	q.where[key] = wrapCondition("$maxDistanceInRadians", &geoDistance{point, distance}, "")
+	return q
+}
+
+// WithinPolygon matches GeoPoints inside the polygon of at least three vertices, the query fails with fewer or nil vertices
+func (q *Query) WithinPolygon(key string, vertices ...*GeoPoint) *Query {
+	q.where[key] = wrapCondition("$polygon", vertices, "")
+	return q
+}
+
+// WithinCenterSphere matches GeoPoints inside the spherical cap centered at the point, with radius in radians.
+// Unlike WithinRadians, results are not sorted by distance
+func (q *Query) WithinCenterSphere(key string, center *GeoPoint, radians float64) *Query {
+	q.where[key] = wrapCondition("$centerSphere", &geoDistance{center, radians}, "")
 	return q
 }
 
@@ -222,9 +240,15 @@ func (q *Query) IncludeACL() *Query {
 	return q
 }
 
+// geoDistance is a center point with a distance from it, used by conditions on GeoPoints
+type geoDistance struct {
+	point    *GeoPoint
+	distance float64
+}
+
 func wrapCondition(verb string, value interface{}, options string) interface{} {
 	switch verb {
-	case "$ne", "$lt", "$lte", "$gt", "$gte", "$in", "$nin", "$all", "nearShpere":
+	case "$ne", "$lt", "$lte", "$gt", "$gte", "$in", "$nin", "$all", "$nearSphere":
 		return map[string]interface{}{
 			verb: encode(value, false),
 		}
@@ -232,6 +256,37 @@ func wrapCondition(verb string, value interface{}, options string) interface{} {
 		return encode(map[string]interface{}{
 			"$box": value,
 		}, true)
+	case "$maxDistanceInKilometers", "$maxDistanceInMiles", "$maxDistanceInRadians":
+		distance := value.(*geoDistance)
+		return map[string]interface{}{
+			"$nearSphere": encodeGeoPoint(distance.point),
+			verb:          distance.distance,
+		}
+	case "$polygon":
+		// invalid polygons are kept as encodeError, which fails the query when sent
+		vertices := value.([]*GeoPoint)
+		if len(vertices) < 3 {
+			return encodeError{fmt.Errorf("unable to query within polygon: at least 3 vertices are required but %d", len(vertices))}
+		}
+		encodedVertices := make([]interface{}, len(vertices))
+		for i, vertex := range vertices {
+			if vertex == nil {
+				return encodeError{fmt.Errorf("unable to query within polygon: vertex %d is nil", i)}
+			}
+			encodedVertices[i] = encodeGeoPoint(vertex)
+		}
+		return map[string]interface{}{
+			"$within": map[string]interface{}{
+				"$polygon": encodedVertices,
+			},
+		}
+	case "$centerSphere":
+		distance := value.(*geoDistance)
+		return map[string]interface{}{
+			"$within": map[string]interface{}{
+				"$centerSphere": []interface{}{encodeGeoPoint(distance.point), distance.distance},
+			},
+		}
 	case "$regex":
 		return map[string]interface{}{
 			"$regex":   value,
@@ -313,8 +368,14 @@ func objectQuery(query interface{}, objects interface{}, count bool, first bool,
 	}
 
 	results := respJSON["results"].([]interface{})
-	switch query.(type) {
+	switch v := query.(type) {
 	case *Query:
+		if v.class.Name == "_File" {
+			if err := bindFiles(results, objects, first); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		}
 		decodedObjects, err := decodeArray(results, true)
 		if err != nil {
 			return nil, err
@@ -334,12 +395,53 @@ func objectQuery(query interface{}, objects interface{}, count bool, first bool,
 	return nil, nil
 }
 
+// bindFiles decodes _File rows into *File, *[]File or *[]*File
+func bindFiles(results []interface{}, objects interface{}, first bool) error {
+	var files []*File
+	for _, result := range results {
+		fields, ok := result.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected error when parse File from response: want type map[string]interface{} but %v", reflect.TypeOf(result))
+		}
+		file, err := decodeFile(fields)
+		if err != nil {
+			return err
+		}
+		if file == nil {
+			return fmt.Errorf("unexpected error when parse File from response: objectId is missing")
+		}
+		files = append(files, file)
+	}
+
+	switch dst := objects.(type) {
+	case *File:
+		if !first {
+			return fmt.Errorf("files should be *[]File or *[]*File but %v", reflect.TypeOf(objects))
+		}
+		if len(files) > 0 {
+			*dst = *files[0]
+		}
+	case *[]File:
+		*dst = make([]File, len(files))
+		for i, file := range files {
+			(*dst)[i] = *file
+		}
+	case *[]*File:
+		*dst = files
+	default:
+		return fmt.Errorf("files should be *File, *[]File or *[]*File but %v", reflect.TypeOf(objects))
+	}
+
+	return nil
+}
+
 func wrapParams(query interface{}, count, first bool) (map[string]string, error) {
 	var where map[string]interface{}
 	var order string
 	var include string
 	var keys string
 	var skip, limit int
+	var includeACL bool
 
 	switch v := query.(type) {
 	case *Query:
@@ -348,6 +450,7 @@ func wrapParams(query interface{}, count, first bool) (map[string]string, error)
 		include = strings.Join(v.include, ",")
 		keys = strings.Join(v.keys, ",")
 		skip, limit = v.skip, v.limit
+		includeACL = v.includeACL
 	}
 
 	whereString, err := json.Marshal(where)
@@ -379,6 +482,10 @@ func wrapParams(query interface{}, count, first bool) (map[string]string, error)
 		params["keys"] = keys
 	}
 
+	if includeACL {
+		params["returnACL"] = "true"
+	}
+
 	if count {
 		params["count"] = "1"
 	}
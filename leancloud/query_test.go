@@ -1,6 +1,8 @@
 package leancloud
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 )
@@ -155,3 +157,114 @@ func TestQueryNotMatchesQuery(t *testing.T) {
 		}
 	}
 }
+
+func TestBindFiles(t *testing.T) {
+	results := []interface{}{
+		map[string]interface{}{
+			"objectId":  "file1",
+			"createdAt": "2020-01-01T00:00:00.000Z",
+			"updatedAt": "2020-01-02T00:00:00.000Z",
+			"name":      "a.txt",
+			"url":       "https://example.com/a.txt",
+			"mime_type": "text/plain",
+			"metaData":  map[string]interface{}{"size": float64(12), "owner": "unknown"},
+			"ACL":       map[string]interface{}{"*": map[string]interface{}{"read": true}},
+		},
+		map[string]interface{}{
+			"objectId": "file2",
+			"name":     "b.txt",
+		},
+	}
+
+	t.Run("Slice", func(t *testing.T) {
+		var files []File
+		if err := bindFiles(results, &files, false); err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 2 {
+			t.Fatalf("want 2 files but %d", len(files))
+		}
+		if files[0].ID != "file1" || files[0].Name != "a.txt" || files[0].MIME != "text/plain" || files[0].Size != 12 {
+			t.Fatal("dismatch file")
+		}
+		if files[0].CreatedAt.IsZero() || files[0].Meatadata["owner"] != "unknown" {
+			t.Fatal("dismatch file")
+		}
+		if _, ok := files[0].fields["ACL"].(*ACL); !ok {
+			t.Fatal("ACL not decoded")
+		}
+	})
+
+	t.Run("PointerSlice", func(t *testing.T) {
+		var files []*File
+		if err := bindFiles(results, &files, false); err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 2 || files[1].ID != "file2" {
+			t.Fatal("dismatch files")
+		}
+	})
+
+	t.Run("First", func(t *testing.T) {
+		file := new(File)
+		if err := bindFiles(results, file, true); err != nil {
+			t.Fatal(err)
+		}
+		if file.ID != "file1" {
+			t.Fatal("dismatch file")
+		}
+	})
+}
+
+func TestQueryGeoConditions(t *testing.T) {
+	point := &GeoPoint{Latitude: 39.9, Longitude: 116.4}
+	tests := []struct {
+		query *Query
+		want  string
+	}{
+		{
+			c.Class("Store").NewQuery().Near("location", point),
+			`{"location":{"$nearSphere":{"__type":"GeoPoint","latitude":39.9,"longitude":116.4}}}`,
+		},
+		{
+			c.Class("Store").NewQuery().WithinKilometers("location", point, 5),
+			`{"location":{"$maxDistanceInKilometers":5,"$nearSphere":{"__type":"GeoPoint","latitude":39.9,"longitude":116.4}}}`,
+		},
+		{
+			c.Class("Store").NewQuery().WithinMiles("location", point, 3),
+			`{"location":{"$maxDistanceInMiles":3,"$nearSphere":{"__type":"GeoPoint","latitude":39.9,"longitude":116.4}}}`,
+		},
+		{
+			c.Class("Store").NewQuery().WithinPolygon("location", &GeoPoint{0, 0}, &GeoPoint{0, 1}, &GeoPoint{1, 0}),
+			`{"location":{"$within":{"$polygon":[{"__type":"GeoPoint","latitude":0,"longitude":0},{"__type":"GeoPoint","latitude":0,"longitude":1},{"__type":"GeoPoint","latitude":1,"longitude":0}]}}}`,
+		},
+		{
+			c.Class("Store").NewQuery().WithinCenterSphere("location", point, 0.1),
+			`{"location":{"$within":{"$centerSphere":[{"__type":"GeoPoint","latitude":39.9,"longitude":116.4},0.1]}}}`,
+		},
+	}
+
+	for _, test := range tests {
+		where, err := json.Marshal(test.query.where)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(where) != test.want {
+			t.Errorf("want %s but %s", test.want, string(where))
+		}
+	}
+}
+
+func TestQueryInvalidPolygon(t *testing.T) {
+	tests := map[string]*Query{
+		"at least 3 vertices are required but 2": c.Class("Store").NewQuery().WithinPolygon("location", &GeoPoint{0, 0}, &GeoPoint{0, 1}),
+		"vertex 1 is nil":                        c.Class("Store").NewQuery().WithinPolygon("location", &GeoPoint{0, 0}, nil, &GeoPoint{1, 0}),
+	}
+
+	for want, query := range tests {
+		var stores []Object
+		if err := query.Find(&stores); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("want error of %s but %v", want, err)
+		}
+	}
+}
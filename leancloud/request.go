@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"runtime"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/levigross/grequests"
@@ -12,7 +13,7 @@ import (
 
 type requestMethod string
 
-var requestCount int
+var requestCount int64
 
 const (
 	methodGet    requestMethod = "GET"
@@ -71,13 +72,19 @@ func (client *Client) getServerURL() string {
 }
 
 func (client *Client) getRequestOptions() *grequests.RequestOptions {
-	return &grequests.RequestOptions{
+	options := &grequests.RequestOptions{
 		UserAgent: getUserAgent(),
 		Headers: map[string]string{
 			"X-LC-Id":  client.appID,
 			"X-LC-Key": client.appKey,
 		},
 	}
+
+	for _, authOption := range client.authOptions {
+		authOption.apply(client, options)
+	}
+
+	return options
 }
 
 func (client *Client) request(method requestMethod, path string, options *grequests.RequestOptions, authOptions ...AuthOption) (*grequests.Response, error) {
@@ -91,8 +98,7 @@ func (client *Client) request(method requestMethod, path string, options *greque
 
 	URL := fmt.Sprint(client.getServerURL(), path)
 
-	requestID := requestCount
-	requestCount++
+	requestID := atomic.AddInt64(&requestCount, 1) - 1
 
 	if client.requestLogger != nil {
 		client.requestLogger.Printf("[REQUEST] request(%d) %s %s %#v\n", requestID, method, URL, options)
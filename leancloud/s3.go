@@ -0,0 +1,150 @@
+package leancloud
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm     = "AWS4-HMAC-SHA256"
+	s3DefaultRegion = "us-east-1"
+	s3TimeFormat    = "20060102T150405Z"
+	s3DateFormat    = "20060102"
+)
+
+// s3PresignParams are parameters of presigned URLs, which authenticate the request in place of the Authorization header
+var s3PresignParams = map[string]bool{
+	"X-Amz-Algorithm":      true,
+	"X-Amz-Credential":     true,
+	"X-Amz-Date":           true,
+	"X-Amz-Expires":        true,
+	"X-Amz-SignedHeaders":  true,
+	"X-Amz-Signature":      true,
+	"X-Amz-Security-Token": true,
+}
+
+// S3Credentials contains the access key of the AWS S3 bucket used by the application,
+// which is required by operations beyond the token issued by LeanCloud, e.g. multipart uploads
+type S3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	Region          string
+}
+
+func (credentials *S3Credentials) region() string {
+	if credentials.Region == "" {
+		return s3DefaultRegion
+	}
+
+	return credentials.Region
+}
+
+// sign signs the request with AWS Signature Version 4, payloadHash is the hex encoded SHA-256 of the body
+func (credentials *S3Credentials) sign(req *http.Request, payloadHash string, now time.Time) {
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	canonicalHeaders := fmt.Sprint(
+		"host:", req.URL.Host, "\n",
+		"x-amz-content-sha256:", payloadHash, "\n",
+		"x-amz-date:", now.Format(s3TimeFormat), "\n",
+	)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := credentials.scope(now)
+	signature := credentials.signature(now, canonicalRequest)
+
+	req.Header.Set("Authorization", fmt.Sprint(s3Algorithm,
+		" Credential=", credentials.AccessKeyID, "/", scope,
+		", SignedHeaders=", strings.Join(signedHeaders, ";"),
+		", Signature=", signature))
+}
+
+func (credentials *S3Credentials) scope(now time.Time) string {
+	return fmt.Sprint(now.Format(s3DateFormat), "/", credentials.region(), "/s3/aws4_request")
+}
+
+func (credentials *S3Credentials) signature(now time.Time, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3TimeFormat),
+		credentials.scope(now),
+		s3Hash([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := s3HMAC([]byte(fmt.Sprint("AWS4", credentials.SecretAccessKey)), now.Format(s3DateFormat))
+	key = s3HMAC(key, credentials.region())
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+
+	return hex.EncodeToString(s3HMAC(key, stringToSign))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, fmt.Sprint(s3Escape(key, true), "=", s3Escape(value, true)))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func s3EscapePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	return s3Escape(path, false)
+}
+
+func s3Escape(s string, escapeSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(s) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' || (b == '/' && !escapeSlash) {
+			builder.WriteByte(b)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,262 @@
+package leancloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Schemas manages schemas, indexes and class-level permissions of classes, which requires the master key
+type Schemas struct {
+	c *Client
+}
+
+// ClassSchema is the schema of a class
+type ClassSchema struct {
+	ClassName             string                  `json:"className"`
+	Fields                map[string]*SchemaField `json:"fields"`
+	Indexes               map[string]SchemaIndex  `json:"indexes,omitempty"`
+	ClassLevelPermissions ClassLevelPermissions   `json:"classLevelPermissions,omitempty"`
+}
+
+// SchemaField describes a field of the class
+type SchemaField struct {
+	// Type is one of String, Number, Boolean, Date, Object, Array, GeoPoint, File, Bytes, Pointer, Relation and ACL
+	Type string `json:"type"`
+
+	// ClassName is the target class of Pointer and Relation
+	ClassName string `json:"className,omitempty"`
+
+	Required     bool        `json:"required,omitempty"`
+	DefaultValue interface{} `json:"default,omitempty"`
+	Comment      string      `json:"comment,omitempty"`
+}
+
+// SchemaIndex is the keys of an index in order, with 1 for ascending and -1 for descending
+type SchemaIndex []SchemaIndexKey
+
+// SchemaIndexKey is a key of SchemaIndex
+type SchemaIndexKey struct {
+	Field     string
+	Direction int
+}
+
+// ClassLevelPermissions maps operations, e.g. get, find, create, update, delete and addField,
+// to users, roles (with role: prefix) or * allowed to perform them
+type ClassLevelPermissions map[string]map[string]bool
+
+// Set allows or disallows the user, the role (with role: prefix) or * to perform the operation
+func (clp ClassLevelPermissions) Set(operation, key string, allowed bool) {
+	if clp[operation] == nil {
+		clp[operation] = make(map[string]bool)
+	}
+
+	if allowed {
+		clp[operation][key] = true
+	} else {
+		delete(clp[operation], key)
+	}
+}
+
+// Allowed reports whether the user, the role (with role: prefix) or * is allowed to perform the operation
+func (clp ClassLevelPermissions) Allowed(operation, key string) bool {
+	return clp[operation][key]
+}
+
+// MarshalJSON encodes keys of the index in order, e.g. {"name":1,"createdAt":-1}
+func (index SchemaIndex) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for i, key := range index {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		field, err := json.Marshal(key.Field)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(buf, "%s:%d", field, key.Direction)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes keys of the index in order
+func (index *SchemaIndex) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("unexpected index %s: want object", string(data))
+	}
+
+	*index = nil
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		field, _ := token.(string)
+
+		var direction int
+		if err := decoder.Decode(&direction); err != nil {
+			return fmt.Errorf("unexpected direction of index key %s: %v", field, err)
+		}
+
+		*index = append(*index, SchemaIndexKey{
+			Field:     field,
+			Direction: direction,
+		})
+	}
+
+	return nil
+}
+
+// List returns schemas of all classes sorted by class name
+func (ref *Schemas) List(authOptions ...AuthOption) ([]*ClassSchema, error) {
+	resp, err := ref.c.request(methodGet, "/1.1/schemas", ref.c.getRequestOptions(), ref.authOptions(authOptions)...)
+	if err != nil {
+		return nil, err
+	}
+
+	respJSON := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(resp.Bytes(), &respJSON); err != nil {
+		return nil, fmt.Errorf("unable to parse response %w", err)
+	}
+
+	var schemas []*ClassSchema
+	if results, ok := respJSON["results"]; ok {
+		if err := json.Unmarshal(results, &schemas); err != nil {
+			return nil, fmt.Errorf("unable to parse response %w", err)
+		}
+	} else {
+		// some servers return fields of classes keyed by class name
+		for className, fields := range respJSON {
+			schema := &ClassSchema{
+				ClassName: className,
+			}
+			if err := json.Unmarshal(fields, &schema.Fields); err != nil {
+				return nil, fmt.Errorf("unable to parse schema of %s %w", className, err)
+			}
+			schemas = append(schemas, schema)
+		}
+	}
+
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].ClassName < schemas[j].ClassName
+	})
+
+	return schemas, nil
+}
+
+// Get returns schema of the class
+func (ref *Schemas) Get(className string, authOptions ...AuthOption) (*ClassSchema, error) {
+	resp, err := ref.c.request(methodGet, fmt.Sprint("/1.1/schemas/", className), ref.c.getRequestOptions(), ref.authOptions(authOptions)...)
+	if err != nil {
+		return nil, err
+	}
+
+	respJSON := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(resp.Bytes(), &respJSON); err != nil {
+		return nil, fmt.Errorf("unable to parse response %w", err)
+	}
+
+	schema := new(ClassSchema)
+	if _, ok := respJSON["fields"]; ok {
+		if err := json.Unmarshal(resp.Bytes(), schema); err != nil {
+			return nil, fmt.Errorf("unable to parse response %w", err)
+		}
+	} else {
+		// some servers return fields of the class keyed by name, as List does
+		if err := json.Unmarshal(resp.Bytes(), &schema.Fields); err != nil {
+			return nil, fmt.Errorf("unable to parse schema of %s %w", className, err)
+		}
+	}
+	if schema.ClassName == "" {
+		schema.ClassName = className
+	}
+
+	return schema, nil
+}
+
+// AddField adds the field to the class, the class is created if not exists
+func (ref *Schemas) AddField(className, name string, field *SchemaField, authOptions ...AuthOption) error {
+	return ref.update(className, map[string]interface{}{
+		"fields": map[string]interface{}{
+			name: field,
+		},
+	}, authOptions...)
+}
+
+// RemoveField removes the field and its data from the class
+func (ref *Schemas) RemoveField(className, name string, authOptions ...AuthOption) error {
+	return ref.update(className, map[string]interface{}{
+		"fields": map[string]interface{}{
+			name: map[string]interface{}{
+				"__op": "Delete",
+			},
+		},
+	}, authOptions...)
+}
+
+// CreateIndex creates the index named name on keys of the class
+func (ref *Schemas) CreateIndex(className, name string, keys SchemaIndex, authOptions ...AuthOption) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("unable to create index %s: keys are missing", name)
+	}
+
+	return ref.update(className, map[string]interface{}{
+		"indexes": map[string]interface{}{
+			name: keys,
+		},
+	}, authOptions...)
+}
+
+// DropIndex drops the index named name of the class
+func (ref *Schemas) DropIndex(className, name string, authOptions ...AuthOption) error {
+	return ref.update(className, map[string]interface{}{
+		"indexes": map[string]interface{}{
+			name: map[string]interface{}{
+				"__op": "Delete",
+			},
+		},
+	}, authOptions...)
+}
+
+// GetCLP returns class-level permissions of the class
+func (ref *Schemas) GetCLP(className string, authOptions ...AuthOption) (ClassLevelPermissions, error) {
+	schema, err := ref.Get(className, authOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.ClassLevelPermissions == nil {
+		return make(ClassLevelPermissions), nil
+	}
+
+	return schema.ClassLevelPermissions, nil
+}
+
+// UpdateCLP replaces class-level permissions of the class
+func (ref *Schemas) UpdateCLP(className string, clp ClassLevelPermissions, authOptions ...AuthOption) error {
+	return ref.update(className, map[string]interface{}{
+		"classLevelPermissions": clp,
+	}, authOptions...)
+}
+
+func (ref *Schemas) update(className string, body map[string]interface{}, authOptions ...AuthOption) error {
+	body["className"] = className
+
+	options := ref.c.getRequestOptions()
+	options.JSON = body
+
+	if _, err := ref.c.request(methodPut, fmt.Sprint("/1.1/schemas/", className), options, ref.authOptions(authOptions)...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ref *Schemas) authOptions(authOptions []AuthOption) []AuthOption {
+	return append([]AuthOption{UseMasterKey(true)}, authOptions...)
+}
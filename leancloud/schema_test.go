@@ -0,0 +1,140 @@
+package leancloud
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSchemaIndexJSON(t *testing.T) {
+	index := SchemaIndex{{"name", 1}, {"createdAt", -1}}
+	data, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"name":1,"createdAt":-1}` {
+		t.Fatalf("unexpected JSON %s", string(data))
+	}
+
+	var decodedIndex SchemaIndex
+	if err := json.Unmarshal(data, &decodedIndex); err != nil {
+		t.Fatal(err)
+	}
+	if len(decodedIndex) != 2 || decodedIndex[0] != index[0] || decodedIndex[1] != index[1] {
+		t.Fatalf("unexpected index %v", decodedIndex)
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	var lastBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-LC-Key") != "master,master" {
+			t.Errorf("master key not used")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/1.1/schemas":
+			w.Write([]byte(`{"Post":{"title":{"type":"String"}},"Comment":{"post":{"type":"Pointer","className":"Post"}}}`))
+		case r.Method == "GET" && r.URL.Path == "/1.1/schemas/Post":
+			w.Write([]byte(`{"className":"Post","fields":{"title":{"type":"String","required":true}},"indexes":{"title_1":{"title":1}},"classLevelPermissions":{"find":{"*":true}}}`))
+		case r.Method == "GET" && r.URL.Path == "/1.1/schemas/Comment":
+			w.Write([]byte(`{"objectId":{"type":"String"},"post":{"type":"Pointer","className":"Post"},"content":{"type":"String"}}`))
+		case r.Method == "PUT" && r.URL.Path == "/1.1/schemas/Post":
+			body, _ := ioutil.ReadAll(r.Body)
+			lastBody = string(body)
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":404,"error":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		AppID:     "app",
+		AppKey:    "key",
+		MasterKey: "master",
+		ServerURL: server.URL,
+	})
+
+	schemas, err := client.Schemas.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schemas) != 2 || schemas[0].ClassName != "Comment" || schemas[0].Fields["post"].ClassName != "Post" {
+		t.Fatal("dismatch schemas")
+	}
+
+	schema, err := client.Schemas.Get("Post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schema.Fields["title"].Required || schema.Indexes["title_1"][0].Field != "title" {
+		t.Fatal("dismatch schema")
+	}
+
+	schema, err = client.Schemas.Get("Comment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.ClassName != "Comment" || len(schema.Fields) != 3 || schema.Fields["post"].ClassName != "Post" {
+		t.Fatalf("dismatch schema of flat fields %v", schema)
+	}
+	if err := client.Schemas.Check("Comment", &struct {
+		Object
+		Content string `json:"content"`
+	}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	clp, err := client.Schemas.GetCLP("Post")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !clp.Allowed("find", "*") || clp.Allowed("delete", "*") {
+		t.Fatal("dismatch CLP")
+	}
+
+	clp.Set("delete", "role:admin", true)
+	if err := client.Schemas.UpdateCLP("Post", clp); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(lastBody, `"delete":{"role:admin":true}`) {
+		t.Fatalf("unexpected body %s", lastBody)
+	}
+
+	if err := client.Schemas.AddField("Post", "views", &SchemaField{Type: "Number"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(lastBody, `"fields":{"views":{"type":"Number"}}`) {
+		t.Fatalf("unexpected body %s", lastBody)
+	}
+
+	if err := client.Schemas.RemoveField("Post", "views"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(lastBody, `"fields":{"views":{"__op":"Delete"}}`) {
+		t.Fatalf("unexpected body %s", lastBody)
+	}
+
+	if err := client.Schemas.CreateIndex("Post", "title_views", SchemaIndex{{"title", 1}, {"views", -1}}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(lastBody, `"indexes":{"title_views":{"title":1,"views":-1}}`) {
+		t.Fatalf("unexpected body %s", lastBody)
+	}
+
+	if err := client.Schemas.DropIndex("Post", "title_views"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(lastBody, `"indexes":{"title_views":{"__op":"Delete"}}`) {
+		t.Fatalf("unexpected body %s", lastBody)
+	}
+
+	if _, err := client.Schemas.Get("Unknown"); err == nil {
+		t.Fatal("unexpected schema of unknown class")
+	}
+}
@@ -0,0 +1,226 @@
+package leancloud
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaMismatchKind is the kind of SchemaMismatch
+type SchemaMismatchKind string
+
+const (
+	// SchemaFieldMissing means the field of the struct does not exist in the schema
+	SchemaFieldMissing SchemaMismatchKind = "missing"
+
+	// SchemaTypeMismatch means the type of the field could not hold values of the type in the schema
+	SchemaTypeMismatch SchemaMismatchKind = "type"
+
+	// SchemaKindUnsupported means the field could not be bound at all, e.g. chan or func
+	SchemaKindUnsupported SchemaMismatchKind = "unsupported"
+)
+
+// SchemaMismatch describes a field of the struct which does not match the schema
+type SchemaMismatch struct {
+	Kind       SchemaMismatchKind
+	Field      string
+	GoType     reflect.Type
+	SchemaType string
+}
+
+func (mismatch *SchemaMismatch) String() string {
+	switch mismatch.Kind {
+	case SchemaFieldMissing:
+		return fmt.Sprintf("field %s does not exist in schema", mismatch.Field)
+	case SchemaTypeMismatch:
+		return fmt.Sprintf("field %s is %s in schema but %v in struct", mismatch.Field, mismatch.SchemaType, mismatch.GoType)
+	default:
+		return fmt.Sprintf("field %s of %v is not supported", mismatch.Field, mismatch.GoType)
+	}
+}
+
+// SchemaCheckError is returned by Schemas.Check if the struct does not match the schema
+type SchemaCheckError struct {
+	ClassName  string
+	Mismatches []SchemaMismatch
+}
+
+func (err *SchemaCheckError) Error() string {
+	messages := make([]string, len(err.Mismatches))
+	for i := range err.Mismatches {
+		messages[i] = err.Mismatches[i].String()
+	}
+
+	return fmt.Sprintf("struct dismatch schema of %s: %s", err.ClassName, strings.Join(messages, "; "))
+}
+
+// Check fetches the schema of the class and compares the struct embedding Object against it,
+// returns *SchemaCheckError if any field does not match
+func (ref *Schemas) Check(className string, object interface{}, authOptions ...AuthOption) error {
+	schema, err := ref.Get(className, authOptions...)
+	if err != nil {
+		return err
+	}
+
+	if mismatches := CheckSchema(schema, object); len(mismatches) > 0 {
+		return &SchemaCheckError{
+			ClassName:  className,
+			Mismatches: mismatches,
+		}
+	}
+
+	return nil
+}
+
+// CheckSchema compares fields of the struct against the schema, in the same way as they are bound
+func CheckSchema(schema *ClassSchema, object interface{}) []SchemaMismatch {
+	t := reflect.TypeOf(object)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return []SchemaMismatch{{
+			Kind:   SchemaKindUnsupported,
+			Field:  schema.ClassName,
+			GoType: t,
+		}}
+	}
+
+	var mismatches []SchemaMismatch
+	for _, structField := range structFields(t) {
+		field := t.FieldByIndex(structField.index)
+		name := structField.name
+
+		if !isBindableType(field.Type) {
+			mismatches = append(mismatches, SchemaMismatch{
+				Kind:   SchemaKindUnsupported,
+				Field:  name,
+				GoType: field.Type,
+			})
+			continue
+		}
+
+		schemaField, ok := schema.Fields[name]
+		if !ok {
+			if !isBuiltinField(schema.ClassName, name) {
+				mismatches = append(mismatches, SchemaMismatch{
+					Kind:   SchemaFieldMissing,
+					Field:  name,
+					GoType: field.Type,
+				})
+			}
+			continue
+		}
+
+		if !isCompatibleType(schemaField.Type, field.Type) {
+			mismatches = append(mismatches, SchemaMismatch{
+				Kind:       SchemaTypeMismatch,
+				Field:      name,
+				GoType:     field.Type,
+				SchemaType: schemaField.Type,
+			})
+		}
+	}
+
+	return mismatches
+}
+
+func isBuiltinField(className, name string) bool {
+	switch name {
+	case "objectId", "createdAt", "updatedAt", "ACL":
+		return true
+	}
+
+	return className == "_User" && name == "sessionToken"
+}
+
+func isBindableType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer, reflect.Uintptr:
+		return false
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return isBindableType(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && isBindableType(t.Elem())
+	}
+
+	return true
+}
+
+func isCompatibleType(schemaType string, t reflect.Type) bool {
+	if t.Kind() == reflect.Interface {
+		return true
+	}
+
+	// types decoding themselves are bound from values of any type, or from strings by encoding.TextUnmarshaler,
+	// except structs embedding Object, which are always bound as objects
+	if !embedsObject(t) {
+		if implementsUnmarshaler(t, leanCloudUnmarshalerType) {
+			return true
+		}
+		if schemaType == "String" && implementsUnmarshaler(t, textUnmarshalerType) {
+			return true
+		}
+	}
+
+	if schemaType == "Pointer" && t.Kind() == reflect.Struct && embedsObject(t) {
+		// included objects are bound into structs embedding Object, but not pointers to them
+		return true
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch schemaType {
+	case "String":
+		return t.Kind() == reflect.String
+	case "Number":
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return true
+		}
+		return false
+	case "Boolean":
+		return t.Kind() == reflect.Bool
+	case "Date":
+		return t == reflect.TypeOf(time.Time{})
+	case "Object":
+		return t.Kind() == reflect.Map || (t.Kind() == reflect.Struct && !isSDKType(t))
+	case "Array":
+		return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8
+	case "GeoPoint":
+		return t == reflect.TypeOf(GeoPoint{})
+	case "File":
+		return t == reflect.TypeOf(File{})
+	case "Bytes":
+		return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+	case "Pointer":
+		return t == reflect.TypeOf(Object{}) || t == reflect.TypeOf(User{}) || t == reflect.TypeOf(Role{})
+	case "ACL":
+		return t == reflect.TypeOf(ACL{})
+	case "Relation":
+		// relations are not returned as values of fields
+		return false
+	}
+
+	return false
+}
+
+// implementsUnmarshaler reports whether the type or its pointer implements the unmarshaler interface
+func implementsUnmarshaler(t reflect.Type, unmarshaler reflect.Type) bool {
+	return t.Implements(unmarshaler) || (t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(unmarshaler))
+}
+
+func isSDKType(t reflect.Type) bool {
+	switch t {
+	case reflect.TypeOf(time.Time{}), reflect.TypeOf(GeoPoint{}), reflect.TypeOf(File{}), reflect.TypeOf(ACL{}),
+		reflect.TypeOf(Object{}), reflect.TypeOf(User{}), reflect.TypeOf(Role{}):
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+package leancloud
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckSchema(t *testing.T) {
+	schema := &ClassSchema{
+		ClassName: "Post",
+		Fields: map[string]*SchemaField{
+			"title":       {Type: "String"},
+			"views":       {Type: "Number"},
+			"publishedAt": {Type: "Date"},
+			"author":      {Type: "Pointer", ClassName: "_User"},
+			"editor":      {Type: "Pointer", ClassName: "_User"},
+			"cover":       {Type: "File"},
+			"tags":        {Type: "Array"},
+			"extra":       {Type: "Object"},
+			"price":       {Type: "Number"},
+			"level":       {Type: "String"},
+		},
+	}
+
+	type post struct {
+		Object
+		Title       string                 `json:"title"`
+		Views       int                    `json:"views"`
+		PublishedAt *time.Time             `json:"publishedAt"`
+		Author      *Object                `json:"author"`
+		Editor      Staff                  `json:"editor"`
+		Cover       *File                  `json:"cover"`
+		Tags        []string               `json:"tags"`
+		Extra       map[string]interface{} `json:"extra,omitempty"`
+		ACL         *ACL                   `json:"ACL"`
+		Price       testMoney              `json:"price"`
+		Level       *testLevel             `json:"level"`
+		Ignored     chan int               `json:"-"`
+		internal    string
+	}
+
+	if mismatches := CheckSchema(schema, &post{}); len(mismatches) != 0 {
+		t.Fatalf("unexpected mismatches %v", mismatches)
+	}
+
+	type driftedPost struct {
+		Object
+		Title    int         `json:"title"`
+		Subtitle string      `json:"subtitle"`
+		Notify   func()      `json:"notify"`
+		Cover    string      `json:"cover"`
+		Extra    interface{} `json:"extra"`
+		Date     testLevel   `json:"publishedAt"`
+	}
+
+	mismatches := CheckSchema(schema, driftedPost{})
+	want := map[string]SchemaMismatchKind{
+		"title":       SchemaTypeMismatch,
+		"subtitle":    SchemaFieldMissing,
+		"notify":      SchemaKindUnsupported,
+		"cover":       SchemaTypeMismatch,
+		"publishedAt": SchemaTypeMismatch,
+	}
+	if len(mismatches) != len(want) {
+		t.Fatalf("unexpected mismatches %v", mismatches)
+	}
+	for _, mismatch := range mismatches {
+		if want[mismatch.Field] != mismatch.Kind {
+			t.Errorf("unexpected mismatch %s", mismatch.String())
+		}
+	}
+
+	err := &SchemaCheckError{ClassName: "Post", Mismatches: mismatches}
+	if !strings.Contains(err.Error(), "field title is String in schema but int in struct") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
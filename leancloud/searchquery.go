@@ -0,0 +1,247 @@
+package leancloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SearchQuery contain parameters of full-text searches by the app search of LeanCloud
+type SearchQuery struct {
+	c           *Client
+	class       *Class
+	queryString string
+	highlights  []string
+	include     []string
+	keys        []string
+	order       []string
+	sortBuilder *SearchSortBuilder
+	limit       int
+	sid         string
+	hits        int
+	done        bool
+}
+
+// SearchSortBuilder builds sort criteria of SearchQuery, in terms of sort of Elasticsearch
+type SearchSortBuilder struct {
+	fields []map[string]interface{}
+}
+
+// NewSearchQuery constructs a new SearchQuery of the Class with query string in syntax of Elasticsearch query string
+func (ref *Class) NewSearchQuery(queryString string) *SearchQuery {
+	return &SearchQuery{
+		c:           ref.c,
+		class:       ref,
+		queryString: queryString,
+	}
+}
+
+// Highlights sets fields to be highlighted, "*" means all fields
+func (q *SearchQuery) Highlights(keys ...string) *SearchQuery {
+	q.highlights = keys
+	return q
+}
+
+func (q *SearchQuery) Include(keys ...string) *SearchQuery {
+	q.include = append(q.include, keys...)
+	return q
+}
+
+func (q *SearchQuery) Select(keys ...string) *SearchQuery {
+	q.keys = append(q.keys, keys...)
+	return q
+}
+
+// Order sorts results by keys, "-" prefixed keys are sorted in descending order.
+// It is ignored if a SearchSortBuilder is set
+func (q *SearchQuery) Order(keys ...string) *SearchQuery {
+	q.order = keys
+	return q
+}
+
+func (q *SearchQuery) SortBy(builder *SearchSortBuilder) *SearchQuery {
+	q.sortBuilder = builder
+	return q
+}
+
+func (q *SearchQuery) Limit(limit int) *SearchQuery {
+	q.limit = limit
+	return q
+}
+
+// SID sets the sid returned by previous searches to continue from there
+func (q *SearchQuery) SID(sid string) *SearchQuery {
+	q.sid = sid
+	q.done = false
+	return q
+}
+
+// GetSID returns the sid of the latest search, which is empty if there is no more result
+func (q *SearchQuery) GetSID() string {
+	return q.sid
+}
+
+// Hits returns the total number of results of the latest search
+func (q *SearchQuery) Hits() int {
+	return q.hits
+}
+
+// HasMore reports whether there are more results to be found by calling Find again
+func (q *SearchQuery) HasMore() bool {
+	return !q.done
+}
+
+// Reset clears the sid so the next Find starts from the first page
+func (q *SearchQuery) Reset() *SearchQuery {
+	q.sid = ""
+	q.hits = 0
+	q.done = false
+	return q
+}
+
+// Find fetch the next page of results, highlights of each object are available by Object.Highlights
+func (q *SearchQuery) Find(objects interface{}, authOptions ...AuthOption) error {
+	if q.done {
+		return fmt.Errorf("no more results of the search query")
+	}
+
+	params, err := q.wrapParams()
+	if err != nil {
+		return err
+	}
+
+	options := q.c.getRequestOptions()
+	options.Params = params
+
+	resp, err := q.c.request(methodGet, "/1.1/search/select", options, authOptions...)
+	if err != nil {
+		return err
+	}
+
+	respJSON := make(map[string]interface{})
+	if err := json.Unmarshal(resp.Bytes(), &respJSON); err != nil {
+		return fmt.Errorf("unable to parse response %w", err)
+	}
+
+	if hits, ok := respJSON["hits"].(float64); ok {
+		q.hits = int(hits)
+	}
+
+	sid, _ := respJSON["sid"].(string)
+	q.sid = sid
+	q.done = sid == ""
+
+	results, ok := respJSON["results"].([]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected error when parse results from response: want type []interface{} but %v", reflect.TypeOf(respJSON["results"]))
+	}
+
+	decodedObjects, err := decodeArray(results, true)
+	if err != nil {
+		return err
+	}
+
+	return bind(reflect.ValueOf(decodedObjects), reflect.ValueOf(objects).Elem())
+}
+
+func (q *SearchQuery) wrapParams() (map[string]string, error) {
+	params := map[string]string{
+		"q":     q.queryString,
+		"clazz": q.class.Name,
+	}
+
+	if len(q.highlights) != 0 {
+		params["highlights"] = strings.Join(q.highlights, ",")
+	}
+
+	if len(q.include) != 0 {
+		params["include"] = strings.Join(q.include, ",")
+	}
+
+	if len(q.keys) != 0 {
+		params["fields"] = strings.Join(q.keys, ",")
+	}
+
+	if q.limit != 0 {
+		params["limit"] = fmt.Sprintf("%d", q.limit)
+	}
+
+	if q.sid != "" {
+		params["sid"] = q.sid
+	}
+
+	if q.sortBuilder != nil {
+		sortString, err := json.Marshal(q.sortBuilder.fields)
+		if err != nil {
+			return nil, fmt.Errorf("unable to wrap params %w", err)
+		}
+		params["sort"] = string(sortString)
+	} else if len(q.order) != 0 {
+		params["order"] = strings.Join(q.order, ",")
+	}
+
+	return params, nil
+}
+
+// NewSearchSortBuilder constructs an empty SearchSortBuilder
+func NewSearchSortBuilder() *SearchSortBuilder {
+	return new(SearchSortBuilder)
+}
+
+// Ascending sorts by the key in ascending order, mode is one of min, max, sum and avg for array fields,
+// missing is _first or _last for objects without the key. Empty mode and missing use defaults of the server
+func (builder *SearchSortBuilder) Ascending(key, mode, missing string) *SearchSortBuilder {
+	return builder.addField(key, "asc", mode, missing)
+}
+
+// Descending sorts by the key in descending order, see Ascending for mode and missing
+func (builder *SearchSortBuilder) Descending(key, mode, missing string) *SearchSortBuilder {
+	return builder.addField(key, "desc", mode, missing)
+}
+
+// WhereNear sorts by distance between the point and the GeoPoint of the key, unit is one of km, mi and so on
+func (builder *SearchSortBuilder) WhereNear(key string, point *GeoPoint, ascending bool, mode, unit string) *SearchSortBuilder {
+	order := "desc"
+	if ascending {
+		order = "asc"
+	}
+
+	geoDistance := map[string]interface{}{
+		key: map[string]interface{}{
+			"lat": point.Latitude,
+			"lon": point.Longitude,
+		},
+		"order": order,
+	}
+	if mode != "" {
+		geoDistance["mode"] = mode
+	}
+	if unit != "" {
+		geoDistance["unit"] = unit
+	}
+
+	builder.fields = append(builder.fields, map[string]interface{}{
+		"_geo_distance": geoDistance,
+	})
+
+	return builder
+}
+
+func (builder *SearchSortBuilder) addField(key, order, mode, missing string) *SearchSortBuilder {
+	field := map[string]interface{}{
+		"order": order,
+	}
+	if mode != "" {
+		field["mode"] = mode
+	}
+	if missing != "" {
+		field["missing"] = missing
+	}
+
+	builder.fields = append(builder.fields, map[string]interface{}{
+		key: field,
+	})
+
+	return builder
+}
@@ -0,0 +1,81 @@
+package leancloud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchQueryParams(t *testing.T) {
+	client := &Client{}
+	query := client.Class("Post").NewSearchQuery("title:go").
+		Highlights("title", "content").
+		Select("title").
+		Limit(10).
+		SortBy(NewSearchSortBuilder().
+			Descending("score", "", "_last").
+			WhereNear("location", &GeoPoint{Latitude: 39.9, Longitude: 116.4}, true, "", "km"))
+
+	params, err := query.wrapParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if params["q"] != "title:go" || params["clazz"] != "Post" || params["highlights"] != "title,content" || params["fields"] != "title" || params["limit"] != "10" {
+		t.Fatalf("dismatch params %v", params)
+	}
+
+	wantSort := `[{"score":{"missing":"_last","order":"desc"}},{"_geo_distance":{"location":{"lat":39.9,"lon":116.4},"order":"asc","unit":"km"}}]`
+	if params["sort"] != wantSort {
+		t.Fatalf("dismatch sort %s", params["sort"])
+	}
+}
+
+func TestSearchQueryFind(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1.1/search/select" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("sid") == "" {
+			w.Write([]byte(`{"hits":2,"sid":"next","results":[{"objectId":"post1","createdAt":"2020-01-01T00:00:00.000Z","updatedAt":"2020-01-01T00:00:00.000Z","title":"go","_highlight":{"title":["<em>go</em>"]}}]}`))
+		} else {
+			w.Write([]byte(`{"hits":2,"sid":null,"results":[{"objectId":"post2","createdAt":"2020-01-01T00:00:00.000Z","updatedAt":"2020-01-01T00:00:00.000Z","title":"golang"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		AppID:     "app",
+		AppKey:    "key",
+		ServerURL: server.URL,
+	})
+	query := client.Class("Post").NewSearchQuery("go").Highlights("title")
+
+	var objects []Object
+	if err := query.Find(&objects); err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 || objects[0].ID != "post1" || query.Hits() != 2 || !query.HasMore() {
+		t.Fatal("dismatch first page")
+	}
+	if highlights := objects[0].Highlights(); len(highlights["title"]) != 1 || highlights["title"][0] != "<em>go</em>" {
+		t.Fatalf("dismatch highlights %v", highlights)
+	}
+
+	type post struct {
+		Object
+		Title string `json:"title"`
+	}
+	var posts []post
+	if err := query.Find(&posts); err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 1 || posts[0].Title != "golang" || query.HasMore() {
+		t.Fatal("dismatch second page")
+	}
+
+	if err := query.Find(&posts); err == nil {
+		t.Fatal("find beyond last page")
+	}
+}
@@ -26,6 +26,9 @@ type functionResponse struct {
 
 // Handler takes all requests related to LeanEngine
 func Handler(handler http.Handler) http.Handler {
+	// fails fast if environment variables of LeanEngine are missing
+	engineClient()
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		uri := strings.Split(r.RequestURI, "/")
 		corsHandler(w, r)
@@ -321,7 +324,7 @@ func constructRequest(r *http.Request, name string, rpc bool) (*FunctionRequest,
 	}
 
 	if functions[name].defineOption["fetchUser"] == true && sessionToken != "" {
-		user, err := client.Users.Become(sessionToken)
+		user, err := engineClient().Users.becomeWithCache(sessionToken)
 		if err != nil {
 			return nil, err
 		}
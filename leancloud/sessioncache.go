@@ -0,0 +1,132 @@
+package leancloud
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSessionCacheSize = 1000
+	defaultSessionCacheTTL  = time.Minute * 5
+)
+
+// SessionCache stores users resolved from session tokens, so that repeated
+// requests carrying the same token need not fetch /users/me again
+type SessionCache interface {
+	// Get returns the cached user of the session token, if any
+	Get(sessionToken string) (*User, bool)
+
+	// Set stores the user resolved from the session token
+	Set(sessionToken string, user *User)
+
+	// Remove discards the cached user of the session token
+	Remove(sessionToken string)
+
+	// RemoveUser discards every cached session of the user
+	RemoveUser(userID string)
+}
+
+type lruSessionEntry struct {
+	sessionToken string
+	user         User
+	expiresAt    time.Time
+}
+
+type lruSessionCache struct {
+	mutex    sync.Mutex
+	capacity int
+	ttl      time.Duration
+	entries  *list.List
+	tokens   map[string]*list.Element
+}
+
+// NewLRUSessionCache constructs an in-memory SessionCache holding at most capacity sessions,
+// each of them expires after ttl. A non-positive ttl means sessions never expire.
+func NewLRUSessionCache(capacity int, ttl time.Duration) SessionCache {
+	return &lruSessionCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  list.New(),
+		tokens:   make(map[string]*list.Element),
+	}
+}
+
+func (cache *lruSessionCache) Get(sessionToken string) (*User, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, ok := cache.tokens[sessionToken]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruSessionEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		cache.removeElement(element)
+		return nil, false
+	}
+
+	cache.entries.MoveToFront(element)
+	user := entry.user
+	return &user, true
+}
+
+func (cache *lruSessionCache) Set(sessionToken string, user *User) {
+	if user == nil || cache.capacity <= 0 {
+		return
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	var expiresAt time.Time
+	if cache.ttl > 0 {
+		expiresAt = time.Now().Add(cache.ttl)
+	}
+
+	if element, ok := cache.tokens[sessionToken]; ok {
+		entry := element.Value.(*lruSessionEntry)
+		entry.user = *user
+		entry.expiresAt = expiresAt
+		cache.entries.MoveToFront(element)
+		return
+	}
+
+	cache.tokens[sessionToken] = cache.entries.PushFront(&lruSessionEntry{
+		sessionToken: sessionToken,
+		user:         *user,
+		expiresAt:    expiresAt,
+	})
+
+	for cache.entries.Len() > cache.capacity {
+		cache.removeElement(cache.entries.Back())
+	}
+}
+
+func (cache *lruSessionCache) Remove(sessionToken string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, ok := cache.tokens[sessionToken]; ok {
+		cache.removeElement(element)
+	}
+}
+
+func (cache *lruSessionCache) RemoveUser(userID string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	for element := cache.entries.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*lruSessionEntry).user.ID == userID {
+			cache.removeElement(element)
+		}
+		element = next
+	}
+}
+
+func (cache *lruSessionCache) removeElement(element *list.Element) {
+	entry := cache.entries.Remove(element).(*lruSessionEntry)
+	delete(cache.tokens, entry.sessionToken)
+}
@@ -0,0 +1,90 @@
+package leancloud
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUSessionCache(t *testing.T) {
+	t.Run("Get/Set", func(t *testing.T) {
+		cache := NewLRUSessionCache(2, time.Minute)
+		cache.Set("token1", &User{Object: Object{ID: "user1"}})
+
+		user, ok := cache.Get("token1")
+		if !ok {
+			t.Fatal("session not cached")
+		}
+		if user.ID != "user1" {
+			t.Fatal("dismatch user")
+		}
+
+		if _, ok := cache.Get("token2"); ok {
+			t.Fatal("unexpected session")
+		}
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		cache := NewLRUSessionCache(2, time.Minute)
+		cache.Set("token1", &User{Object: Object{ID: "user1"}})
+		cache.Set("token2", &User{Object: Object{ID: "user2"}})
+		cache.Get("token1")
+		cache.Set("token3", &User{Object: Object{ID: "user3"}})
+
+		if _, ok := cache.Get("token2"); ok {
+			t.Fatal("least recently used session not evicted")
+		}
+		if _, ok := cache.Get("token1"); !ok {
+			t.Fatal("recently used session evicted")
+		}
+	})
+
+	t.Run("Expiration", func(t *testing.T) {
+		cache := NewLRUSessionCache(2, time.Millisecond)
+		cache.Set("token1", &User{Object: Object{ID: "user1"}})
+		time.Sleep(time.Millisecond * 5)
+
+		if _, ok := cache.Get("token1"); ok {
+			t.Fatal("expired session returned")
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		cache := NewLRUSessionCache(3, time.Minute)
+		cache.Set("token1", &User{Object: Object{ID: "user1"}})
+		cache.Set("token2", &User{Object: Object{ID: "user1"}})
+		cache.Set("token3", &User{Object: Object{ID: "user2"}})
+
+		cache.Remove("token3")
+		if _, ok := cache.Get("token3"); ok {
+			t.Fatal("removed session returned")
+		}
+
+		cache.RemoveUser("user1")
+		if _, ok := cache.Get("token1"); ok {
+			t.Fatal("removed session returned")
+		}
+		if _, ok := cache.Get("token2"); ok {
+			t.Fatal("removed session returned")
+		}
+	})
+}
+
+func TestEvictSession(t *testing.T) {
+	cache := NewLRUSessionCache(2, time.Minute)
+	client := NewClient(&ClientOptions{
+		AppID:        "app",
+		AppKey:       "key",
+		ServerURL:    "http://127.0.0.1:1",
+		SessionCache: cache,
+	})
+
+	cache.Set("token1", &User{Object: Object{ID: "user1"}})
+	if user, err := client.Users.becomeWithCache("token1"); err != nil || user.ID != "user1" {
+		t.Fatal("cached session not used")
+	}
+
+	client.Users.EvictSession("token1")
+	if _, ok := cache.Get("token1"); ok {
+		t.Fatal("evicted session returned")
+	}
+}
@@ -0,0 +1,167 @@
+package leancloud
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const s3MaxExpires = 7 * 24 * time.Hour
+
+// QiniuCredentials contains the access key of the Qiniu account, which is required by signed URLs of private buckets
+type QiniuCredentials struct {
+	AccessKey string
+	SecretKey string
+}
+
+// COSCredentials contains the API key of the Tencent Cloud account, which is required by signed URLs of private buckets
+type COSCredentials struct {
+	SecretID  string
+	SecretKey string
+}
+
+// SignedURL returns a download URL of the file in private bucket which expires after expires,
+// signed with credentials of the storage provider configured on the client
+func (file *File) SignedURL(client *Client, expires time.Duration) (string, error) {
+	if file.URL == "" {
+		return "", fmt.Errorf("unable to sign URL: url is empty")
+	}
+
+	if expires <= 0 {
+		return "", fmt.Errorf("unable to sign URL: expires should be positive but %v", expires)
+	}
+
+	provider, err := getStorageProvider(file.Provider)
+	if err != nil {
+		return "", err
+	}
+
+	signer, ok := provider.(StorageURLSigner)
+	if !ok {
+		return "", fmt.Errorf("signed URL is not supported by provider %s", file.Provider)
+	}
+
+	return signer.SignURL(client, file, expires)
+}
+
+func (provider *qiniuStorageProvider) SignURL(client *Client, file *File, expires time.Duration) (string, error) {
+	if client == nil || client.qiniuCredentials == nil {
+		return "", fmt.Errorf("unable to sign URL of Qiniu: QiniuCredentials is not set")
+	}
+
+	return client.qiniuCredentials.signURL(file.URL, time.Now().Add(expires))
+}
+
+func (provider *s3StorageProvider) SignURL(client *Client, file *File, expires time.Duration) (string, error) {
+	if client == nil || client.s3Credentials == nil {
+		return "", fmt.Errorf("unable to sign URL of AWS S3: S3Credentials is not set")
+	}
+
+	return client.s3Credentials.presignURL(file.URL, expires, time.Now())
+}
+
+func (provider *cosStorageProvider) SignURL(client *Client, file *File, expires time.Duration) (string, error) {
+	if client == nil || client.cosCredentials == nil {
+		return "", fmt.Errorf("unable to sign URL of Tencent Cloud COS: COSCredentials is not set")
+	}
+
+	now := time.Now()
+	return client.cosCredentials.signURL(file.URL, now, now.Add(expires))
+}
+
+// signURL signs the URL as a private download link of Qiniu, which is valid until deadline
+func (credentials *QiniuCredentials) signURL(rawURL string, deadline time.Time) (string, error) {
+	if _, err := url.Parse(rawURL); err != nil {
+		return "", fmt.Errorf("unable to sign URL: %v", err)
+	}
+
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+	signedURL := fmt.Sprint(rawURL, separator, "e=", deadline.Unix())
+
+	mac := hmac.New(sha1.New, []byte(credentials.SecretKey))
+	mac.Write([]byte(signedURL))
+	token := fmt.Sprint(credentials.AccessKey, ":", base64.URLEncoding.EncodeToString(mac.Sum(nil)))
+
+	return fmt.Sprint(signedURL, "&token=", token), nil
+}
+
+// presignURL presigns a GET of the URL with AWS Signature Version 4 in query string
+func (credentials *S3Credentials) presignURL(rawURL string, expires time.Duration, now time.Time) (string, error) {
+	if expires > s3MaxExpires {
+		return "", fmt.Errorf("unable to sign URL of AWS S3: expires should be no more than %v but %v", s3MaxExpires, expires)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("unable to sign URL: %v", err)
+	}
+
+	now = now.UTC()
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", fmt.Sprint(credentials.AccessKeyID, "/", credentials.scope(now)))
+	query.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", fmt.Sprint(int64(expires/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := s3CanonicalQuery(query)
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		s3EscapePath(u.Path),
+		canonicalQuery,
+		fmt.Sprint("host:", u.Host, "\n"),
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	u.RawQuery = fmt.Sprint(canonicalQuery, "&X-Amz-Signature=", credentials.signature(now, canonicalRequest))
+
+	return u.String(), nil
+}
+
+// signURL signs the URL with the COS request signature in query string, which is valid from start to end
+func (credentials *COSCredentials) signURL(rawURL string, start, end time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("unable to sign URL: %v", err)
+	}
+
+	keyTime := fmt.Sprint(start.Unix(), ";", end.Unix())
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+
+	httpString := fmt.Sprint("get\n", path, "\n\n\n")
+	httpStringHash := sha1.Sum([]byte(httpString))
+	stringToSign := fmt.Sprint("sha1\n", keyTime, "\n", hex.EncodeToString(httpStringHash[:]), "\n")
+
+	signKey := hmacSHA1Hex([]byte(credentials.SecretKey), keyTime)
+	signature := hmacSHA1Hex([]byte(signKey), stringToSign)
+
+	query := u.Query()
+	query.Set("q-sign-algorithm", "sha1")
+	query.Set("q-ak", credentials.SecretID)
+	query.Set("q-sign-time", keyTime)
+	query.Set("q-key-time", keyTime)
+	query.Set("q-header-list", "")
+	query.Set("q-url-param-list", "")
+	query.Set("q-signature", signature)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+func hmacSHA1Hex(key []byte, data string) string {
+	mac := hmac.New(sha1.New, key)
+	mac.Write([]byte(data))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,71 @@
+package leancloud
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Status is a message published by a user to inboxes of its followers
+type Status struct {
+	ID        string
+	MessageID int64
+	InboxType string
+	Source    *User
+	CreatedAt time.Time
+	Data      map[string]interface{}
+}
+
+func decodeStatus(fields map[string]interface{}) (*Status, error) {
+	status := new(Status)
+	status.Data = make(map[string]interface{})
+
+	decodedFields, err := decodeMap(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range decodedFields {
+		switch key {
+		case "objectId":
+			objectID, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected error when parse objectId: want type string but %v", reflect.TypeOf(value))
+			}
+			status.ID = objectID
+		case "messageId":
+			messageID, ok := value.(float64)
+			if !ok {
+				return nil, fmt.Errorf("unexpected error when parse messageId: want type float64 but %v", reflect.TypeOf(value))
+			}
+			status.MessageID = int64(messageID)
+		case "inboxType":
+			inboxType, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected error when parse inboxType: want type string but %v", reflect.TypeOf(value))
+			}
+			status.InboxType = inboxType
+		case "source":
+			source, ok := value.(*Object)
+			if !ok {
+				return nil, fmt.Errorf("unexpected error when parse source: want type *Object but %v", reflect.TypeOf(value))
+			}
+			status.Source = &User{Object: *source}
+		case "createdAt":
+			createdAt, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected error when parse createdAt: want type string but %v", reflect.TypeOf(value))
+			}
+			decodedCreatedAt, err := time.Parse(time.RFC3339, createdAt)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected error when parse createdAt: %v", err)
+			}
+			status.CreatedAt = decodedCreatedAt
+		case "updatedAt", "owner":
+		default:
+			status.Data[key] = value
+		}
+	}
+
+	return status, nil
+}
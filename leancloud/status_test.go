@@ -0,0 +1,160 @@
+package leancloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestStatusDecode(t *testing.T) {
+	status, err := decodeStatus(map[string]interface{}{
+		"objectId":  "5f4c6b8a2a6bfd0006b58a3e",
+		"messageId": float64(42),
+		"inboxType": "default",
+		"source": map[string]interface{}{
+			"__type":    "Pointer",
+			"className": "_User",
+			"objectId":  "5f4c6b8a2a6bfd0006b58a3f",
+		},
+		"createdAt": "2020-08-31T03:01:30.654Z",
+		"message":   "Hello World",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.ID != "5f4c6b8a2a6bfd0006b58a3e" || status.MessageID != 42 || status.InboxType != "default" {
+		t.Fatal("dismatch status")
+	}
+
+	if status.Source == nil || status.Source.ID != "5f4c6b8a2a6bfd0006b58a3f" {
+		t.Fatal("dismatch source")
+	}
+
+	if status.CreatedAt.IsZero() {
+		t.Fatal("dismatch createdAt")
+	}
+
+	if status.Data["message"] != "Hello World" {
+		t.Fatal("dismatch data")
+	}
+}
+
+func TestStatuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		if r.URL.Path != "/1.1/statuses" {
+			if query.Get("owner") != `{"__type":"Pointer","className":"_User","objectId":"user2"}` || query.Get("inboxType") != "private" {
+				t.Errorf("unexpected owner or inboxType %s", r.URL.RawQuery)
+			}
+		}
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/1.1/statuses":
+			body := decodeRequestJSON(t, r)
+			data := body["data"].(map[string]interface{})
+			source := data["source"].(map[string]interface{})
+			where := body["query"].(map[string]interface{})["where"].(map[string]interface{})
+			if body["inboxType"] != "default" || data["message"] != "hello" || source["objectId"] != "user1" ||
+				where["user"].(map[string]interface{})["objectId"] != "user1" {
+				t.Errorf("unexpected body %v", body)
+			}
+			w.Write([]byte(`{"objectId":"status1","createdAt":"2020-08-31T03:01:30.654Z"}`))
+		case r.Method == "GET" && r.URL.Path == "/1.1/subscribe/statuses":
+			// newest first, paged by messageId
+			messageIDs := []int{5, 4, 3, 2, 1}
+			var results []string
+			for _, id := range messageIDs {
+				if since, err := strconv.Atoi(query.Get("sinceId")); err == nil && id <= since {
+					continue
+				}
+				if max, err := strconv.Atoi(query.Get("maxId")); err == nil && id > max {
+					continue
+				}
+				if limit, err := strconv.Atoi(query.Get("limit")); err == nil && len(results) == limit {
+					break
+				}
+				results = append(results, fmt.Sprintf(`{"objectId":"status%d","messageId":%d,"inboxType":"private","source":{"__type":"Pointer","className":"_User","objectId":"user1"}}`, id, id))
+			}
+			w.Write([]byte(fmt.Sprintf(`{"results":[%s]}`, strings.Join(results, ","))))
+		case r.Method == "GET" && r.URL.Path == "/1.1/subscribe/statuses/count":
+			w.Write([]byte(`{"total":5,"unread":3}`))
+		case r.Method == "POST" && r.URL.Path == "/1.1/subscribe/statuses/resetUnreadCount":
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":404,"error":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		AppID:     "app",
+		AppKey:    "key",
+		ServerURL: server.URL,
+	})
+
+	status, err := client.Statuses.Publish(client.Users.ID("user1"), "", map[string]interface{}{"message": "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.ID != "status1" || status.InboxType != "default" || status.Source.ID != "user1" || status.CreatedAt.IsZero() {
+		t.Fatalf("dismatch status %v", status)
+	}
+
+	owner := client.Users.ID("user2")
+	statuses, err := client.Statuses.NewInboxQuery(owner, "private").Limit(2).Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].MessageID != 5 || statuses[1].MessageID != 4 {
+		t.Fatalf("dismatch first page %v", statuses)
+	}
+
+	statuses, err = client.Statuses.NewInboxQuery(owner, "private").MaxID(statuses[1].MessageID - 1).Limit(2).Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].MessageID != 3 || statuses[1].MessageID != 2 {
+		t.Fatalf("dismatch next page %v", statuses)
+	}
+
+	statuses, err = client.Statuses.NewInboxQuery(owner, "private").SinceID(3).Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].MessageID != 5 || statuses[1].MessageID != 4 {
+		t.Fatalf("dismatch newer statuses %v", statuses)
+	}
+
+	total, unread, err := client.Statuses.Count(owner, "private")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 5 || unread != 3 {
+		t.Fatalf("unexpected count %d/%d", unread, total)
+	}
+
+	if err := client.Statuses.ResetUnreadCount(owner, "private"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Statuses.Publish(nil, "", nil); err == nil {
+		t.Fatal("status published without source")
+	}
+	if _, _, err := client.Statuses.Count(nil, ""); err == nil {
+		t.Fatal("inbox counted without owner")
+	}
+}
+
+func decodeRequestJSON(t *testing.T, r *http.Request) map[string]interface{} {
+	body := make(map[string]interface{})
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		t.Errorf("unable to decode request body: %v", err)
+	}
+	return body
+}
@@ -0,0 +1,254 @@
+package leancloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+const defaultInboxType = "default"
+
+type Statuses struct {
+	c *Client
+}
+
+// InboxQuery contains parameters of queries on the status inbox of a user
+type InboxQuery struct {
+	c         *Client
+	owner     *UserRef
+	inboxType string
+	sinceID   int64
+	maxID     int64
+	limit     int
+}
+
+// Publish sends a status with data from the source user to inboxes of all its followers
+func (ref *Statuses) Publish(source *UserRef, inboxType string, data map[string]interface{}, authOptions ...AuthOption) (*Status, error) {
+	if source == nil || source.ID == "" {
+		return nil, fmt.Errorf("unable to publish status: no source user referred")
+	}
+
+	if inboxType == "" {
+		inboxType = defaultInboxType
+	}
+
+	encodedData := encodeMap(data, false)
+	encodedData["source"] = encodeUserPointer(source.ID)
+
+	path := "/1.1/statuses"
+	options := ref.c.getRequestOptions()
+	options.JSON = map[string]interface{}{
+		"data":      encodedData,
+		"inboxType": inboxType,
+		"query": map[string]interface{}{
+			"className": "_Follower",
+			"keys":      "follower",
+			"where": map[string]interface{}{
+				"user": encodeUserPointer(source.ID),
+			},
+		},
+	}
+
+	resp, err := ref.c.request(methodPost, path, options, authOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	respJSON := make(map[string]interface{})
+	if err := json.Unmarshal(resp.Bytes(), &respJSON); err != nil {
+		return nil, err
+	}
+
+	objectID, ok := respJSON["objectId"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected error when parse objectId from response: want type string but %v", reflect.TypeOf(respJSON["objectId"]))
+	}
+
+	createdAt, ok := respJSON["createdAt"].(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected error when parse createdAt from response: want type string but %v", reflect.TypeOf(respJSON["createdAt"]))
+	}
+	decodedCreatedAt, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error when parse createdAt from response: %v", err)
+	}
+
+	return &Status{
+		ID:        objectID,
+		InboxType: inboxType,
+		Source:    &User{Object: Object{ID: source.ID}},
+		CreatedAt: decodedCreatedAt,
+		Data:      data,
+	}, nil
+}
+
+// Destroy deletes the status published by its source user
+func (ref *Statuses) Destroy(statusID string, authOptions ...AuthOption) error {
+	path := fmt.Sprint("/1.1/statuses/", statusID)
+	if _, err := ref.c.request(methodDelete, path, ref.c.getRequestOptions(), authOptions...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewInboxQuery constructs a query on statuses in the inbox of the owner
+func (ref *Statuses) NewInboxQuery(owner *UserRef, inboxType string) *InboxQuery {
+	if inboxType == "" {
+		inboxType = defaultInboxType
+	}
+
+	return &InboxQuery{
+		c:         ref.c,
+		owner:     owner,
+		inboxType: inboxType,
+	}
+}
+
+// Count returns the total and unread count of statuses in the inbox of the owner
+func (ref *Statuses) Count(owner *UserRef, inboxType string, authOptions ...AuthOption) (int, int, error) {
+	if inboxType == "" {
+		inboxType = defaultInboxType
+	}
+
+	params, err := wrapInboxParams(owner, inboxType)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	path := "/1.1/subscribe/statuses/count"
+	options := ref.c.getRequestOptions()
+	options.Params = params
+
+	resp, err := ref.c.request(methodGet, path, options, authOptions...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	respJSON := make(map[string]interface{})
+	if err := json.Unmarshal(resp.Bytes(), &respJSON); err != nil {
+		return 0, 0, err
+	}
+
+	total, ok := respJSON["total"].(float64)
+	if !ok {
+		return 0, 0, fmt.Errorf("unable to parse total from response")
+	}
+
+	unread, ok := respJSON["unread"].(float64)
+	if !ok {
+		return 0, 0, fmt.Errorf("unable to parse unread from response")
+	}
+
+	return int(total), int(unread), nil
+}
+
+// ResetUnreadCount marks all statuses in the inbox of the owner as read
+func (ref *Statuses) ResetUnreadCount(owner *UserRef, inboxType string, authOptions ...AuthOption) error {
+	if inboxType == "" {
+		inboxType = defaultInboxType
+	}
+
+	params, err := wrapInboxParams(owner, inboxType)
+	if err != nil {
+		return err
+	}
+
+	path := "/1.1/subscribe/statuses/resetUnreadCount"
+	options := ref.c.getRequestOptions()
+	options.Params = params
+
+	if _, err := ref.c.request(methodPost, path, options, authOptions...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SinceID restricts results to statuses whose messageId is greater than id
+func (q *InboxQuery) SinceID(id int64) *InboxQuery {
+	q.sinceID = id
+	return q
+}
+
+// MaxID restricts results to statuses whose messageId is not greater than id
+func (q *InboxQuery) MaxID(id int64) *InboxQuery {
+	q.maxID = id
+	return q
+}
+
+func (q *InboxQuery) Limit(limit int) *InboxQuery {
+	q.limit = limit
+	return q
+}
+
+// Find fetch statuses in the inbox, from the newest to the oldest
+func (q *InboxQuery) Find(authOptions ...AuthOption) ([]Status, error) {
+	params, err := wrapInboxParams(q.owner, q.inboxType)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.sinceID != 0 {
+		params["sinceId"] = fmt.Sprintf("%d", q.sinceID)
+	}
+
+	if q.maxID != 0 {
+		params["maxId"] = fmt.Sprintf("%d", q.maxID)
+	}
+
+	if q.limit != 0 {
+		params["limit"] = fmt.Sprintf("%d", q.limit)
+	}
+
+	path := "/1.1/subscribe/statuses"
+	options := q.c.getRequestOptions()
+	options.Params = params
+
+	resp, err := q.c.request(methodGet, path, options, authOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	respJSON := make(map[string]interface{})
+	if err := json.Unmarshal(resp.Bytes(), &respJSON); err != nil {
+		return nil, fmt.Errorf("unable to parse response %w", err)
+	}
+
+	results, ok := respJSON["results"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected error when parse results from response: want type []interface{} but %v", reflect.TypeOf(respJSON["results"]))
+	}
+
+	statuses := make([]Status, 0, len(results))
+	for _, result := range results {
+		fields, ok := result.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected error when parse status from response: want type map[string]interface{} but %v", reflect.TypeOf(result))
+		}
+		status, err := decodeStatus(fields)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, *status)
+	}
+
+	return statuses, nil
+}
+
+func wrapInboxParams(owner *UserRef, inboxType string) (map[string]string, error) {
+	if owner == nil || owner.ID == "" {
+		return nil, fmt.Errorf("unable to wrap params: no owner referred")
+	}
+
+	ownerString, err := json.Marshal(encodeUserPointer(owner.ID))
+	if err != nil {
+		return nil, fmt.Errorf("unable to wrap params %w", err)
+	}
+
+	return map[string]string{
+		"owner":     string(ownerString),
+		"inboxType": inboxType,
+	}, nil
+}
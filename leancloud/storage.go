@@ -0,0 +1,154 @@
+package leancloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StorageUpload contains everything a StorageProvider needs to transfer a file
+type StorageUpload struct {
+	File      *File
+	Token     string
+	UploadURL string
+	Reader    io.Reader
+
+	// Size is -1 if unknown
+	Size int64
+
+	// Client is the client uploading the file, through which providers reach credentials configured on it
+	Client *Client
+}
+
+// StorageProvider transfers files to the cloud storage returned by /1.1/fileTokens
+type StorageProvider interface {
+	Upload(ctx context.Context, upload *StorageUpload) error
+}
+
+// StorageDeleter is implemented by providers which remove files from the storage by themselves,
+// rather than leaving it to LeanCloud
+type StorageDeleter interface {
+	Delete(ctx context.Context, file *File) error
+}
+
+// StorageURLSigner is implemented by providers which could sign download URLs of private buckets
+type StorageURLSigner interface {
+	SignURL(client *Client, file *File, expires time.Duration) (string, error)
+}
+
+var storageProviders = map[string]StorageProvider{
+	"qiniu":  new(qiniuStorageProvider),
+	"s3":     new(s3StorageProvider),
+	"qcloud": new(cosStorageProvider),
+}
+
+var storageProvidersMutex sync.RWMutex
+
+// RegisterStorageProvider registers the provider of the name, which replaces the built-in one if any
+func RegisterStorageProvider(name string, provider StorageProvider) {
+	storageProvidersMutex.Lock()
+	defer storageProvidersMutex.Unlock()
+
+	storageProviders[name] = provider
+}
+
+func getStorageProvider(name string) (StorageProvider, error) {
+	storageProvidersMutex.RLock()
+	defer storageProvidersMutex.RUnlock()
+
+	provider, ok := storageProviders[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported storage provider %s", name)
+	}
+
+	return provider, nil
+}
+
+type qiniuStorageProvider struct{}
+
+// Upload sends the file to upload_url issued with the token, and then to hosts of the bucket's region and the default hosts
+// in order, falling back to the next one only if the connection failed before any byte of the file was read
+func (provider *qiniuStorageProvider) Upload(ctx context.Context, upload *StorageUpload) error {
+	reader := &countingReader{reader: upload.Reader}
+	hosts := newQiniuUploadHosts(upload.Token, upload.UploadURL, upload.File.Bucket)
+	var err error
+	for i := 0; ; i++ {
+		host, ok := hosts.get(ctx, i)
+		if !ok {
+			return err
+		}
+		err = upload.File.uploadQiniu(ctx, upload.Token, host, reader)
+		if err == nil || !isConnectionError(err) || reader.count > 0 {
+			return err
+		}
+	}
+}
+
+type s3StorageProvider struct{}
+
+func (provider *s3StorageProvider) Upload(ctx context.Context, upload *StorageUpload) error {
+	if upload.Size >= 0 {
+		return upload.File.uploadS3(ctx, upload.Token, upload.UploadURL, upload.Reader)
+	}
+
+	if upload.Client == nil || upload.Client.s3Credentials == nil {
+		return fmt.Errorf("unable to upload file of unknown size to AWS S3: S3Credentials is not set")
+	}
+
+	return upload.File.uploadS3Multipart(ctx, upload.Client.s3Credentials, upload.UploadURL, upload.Reader)
+}
+
+type cosStorageProvider struct{}
+
+func (provider *cosStorageProvider) Upload(ctx context.Context, upload *StorageUpload) error {
+	return upload.File.uploadCOS(ctx, upload.Token, upload.UploadURL, upload.Reader, upload.Size)
+}
+
+// LocalStorageProvider stores files in a local directory by their keys, which is useful in development and testing.
+// The provider of a file is the one returned by /1.1/fileTokens, which is never "local", so it takes effect only
+// when registered in place of the provider of the application, e.g. RegisterStorageProvider("qiniu", NewLocalStorageProvider(dir))
+type LocalStorageProvider struct {
+	Dir string
+}
+
+// NewLocalStorageProvider constructs a LocalStorageProvider storing files in dir
+func NewLocalStorageProvider(dir string) *LocalStorageProvider {
+	return &LocalStorageProvider{
+		Dir: dir,
+	}
+}
+
+func (provider *LocalStorageProvider) Upload(ctx context.Context, upload *StorageUpload) error {
+	path := provider.path(upload.File)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("unexpected error when upload file to %s: %v", provider.Dir, err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unexpected error when upload file to %s: %v", provider.Dir, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, upload.Reader); err != nil {
+		return fmt.Errorf("unexpected error when upload file to %s: %v", provider.Dir, err)
+	}
+
+	return nil
+}
+
+func (provider *LocalStorageProvider) Delete(ctx context.Context, file *File) error {
+	if err := os.Remove(provider.path(file)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unexpected error when delete file from %s: %v", provider.Dir, err)
+	}
+
+	return nil
+}
+
+func (provider *LocalStorageProvider) path(file *File) string {
+	return filepath.Join(provider.Dir, filepath.FromSlash(filepath.Clean("/"+file.Key)))
+}
@@ -0,0 +1,107 @@
+package leancloud
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStorageProvider(t *testing.T) {
+	t.Run("Unknown", func(t *testing.T) {
+		if _, err := getStorageProvider("unknown"); err == nil {
+			t.Fatal("unknown provider should be rejected")
+		}
+	})
+
+	t.Run("Local", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "go-sdk-storage-")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		registerTestStorageProvider(t, "local", NewLocalStorageProvider(dir))
+		provider, err := getStorageProvider("local")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		file := &File{Key: "gamma/go-sdk-file-upload.txt"}
+		if err := provider.Upload(context.Background(), &StorageUpload{
+			File:   file,
+			Reader: bytes.NewReader([]byte("temporary file's content")),
+			Size:   -1,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := ioutil.ReadFile(filepath.Join(dir, "gamma", "go-sdk-file-upload.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "temporary file's content" {
+			t.Fatal("dismatch content")
+		}
+
+		if err := provider.(StorageDeleter).Delete(context.Background(), file); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "gamma", "go-sdk-file-upload.txt")); !os.IsNotExist(err) {
+			t.Fatal("file not deleted")
+		}
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		provider := new(testStorageProvider)
+		registerTestStorageProvider(t, "custom", provider)
+
+		client := &Client{qiniuCredentials: &QiniuCredentials{AccessKey: "access", SecretKey: "secret"}}
+		file := &File{Provider: "custom", URL: "https://example.com/test.txt"}
+		signedURL, err := file.SignedURL(client, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signedURL != "https://example.com/test.txt?signed=access" {
+			t.Fatalf("dismatch signed URL %s", signedURL)
+		}
+	})
+
+	if _, err := getStorageProvider("local"); err == nil {
+		t.Fatal("provider registered by test not removed")
+	}
+}
+
+// testStorageProvider signs URLs with the access key of Qiniu, to check credentials are reachable by custom providers
+type testStorageProvider struct{}
+
+func (provider *testStorageProvider) Upload(ctx context.Context, upload *StorageUpload) error {
+	return nil
+}
+
+func (provider *testStorageProvider) SignURL(client *Client, file *File, expires time.Duration) (string, error) {
+	return fmt.Sprint(file.URL, "?signed=", client.QiniuCredentials().AccessKey), nil
+}
+
+// registerTestStorageProvider registers the provider until the test finishes
+func registerTestStorageProvider(t *testing.T, name string, provider StorageProvider) {
+	storageProvidersMutex.RLock()
+	previous, ok := storageProviders[name]
+	storageProvidersMutex.RUnlock()
+
+	RegisterStorageProvider(name, provider)
+	t.Cleanup(func() {
+		storageProvidersMutex.Lock()
+		defer storageProvidersMutex.Unlock()
+
+		if ok {
+			storageProviders[name] = previous
+		} else {
+			delete(storageProviders, name)
+		}
+	})
+}
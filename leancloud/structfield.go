@@ -0,0 +1,154 @@
+package leancloud
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structField is a field of user-defined struct mapped to a field of LeanCloud object
+type structField struct {
+	name  string
+	index []int
+
+	// omitEmpty skips the field when encoding if it is zero, set by json:",omitempty" or lc:",omitempty"
+	omitEmpty bool
+
+	// readOnly skips the field when encoding, for server-managed fields, set by lc:",readonly"
+	readOnly bool
+}
+
+var structFieldsCache sync.Map
+
+// structFields returns fields of the struct type in the way of encoding/json:
+// the name is from the lc tag, then the json tag, then the name of the field;
+// fields tagged with "-" and unexported fields are skipped; fields of anonymous structs without name are
+// flattened, and shallower fields take precedence. Embedded Object, User and Role are not included
+func structFields(t reflect.Type) []structField {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.([]structField)
+	}
+
+	var fields []structField
+	depths := make(map[string]int)
+	positions := make(map[string]int)
+
+	var walk func(t reflect.Type, index []int, depth int, visited map[reflect.Type]bool)
+	walk = func(t reflect.Type, index []int, depth int, visited map[reflect.Type]bool) {
+		if visited[t] {
+			return
+		}
+		visited[t] = true
+		defer delete(visited, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			fieldIndex := append(append([]int{}, index...), i)
+
+			jsonName, jsonOptions := parseTagOptions(sf.Tag.Get("json"))
+			lcName, lcOptions := parseTagOptions(sf.Tag.Get("lc"))
+			if jsonName == "-" || lcName == "-" {
+				continue
+			}
+
+			name := lcName
+			if name == "" {
+				name = jsonName
+			}
+
+			if sf.Anonymous {
+				ft := sf.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if isBareType(ft) {
+					continue
+				}
+				if sf.Type.Kind() == reflect.Ptr && sf.PkgPath != "" {
+					// embedded pointers to unexported structs could not be allocated
+					continue
+				}
+				if name == "" && ft.Kind() == reflect.Struct {
+					walk(ft, fieldIndex, depth+1, visited)
+					continue
+				}
+			}
+
+			if sf.PkgPath != "" {
+				continue
+			}
+
+			if name == "" {
+				name = sf.Name
+			}
+
+			field := structField{
+				name:      name,
+				index:     fieldIndex,
+				omitEmpty: jsonOptions["omitempty"] || lcOptions["omitempty"],
+				readOnly:  lcOptions["readonly"],
+			}
+
+			if existingDepth, ok := depths[name]; ok {
+				if depth < existingDepth {
+					fields[positions[name]] = field
+					depths[name] = depth
+				}
+				continue
+			}
+
+			depths[name] = depth
+			positions[name] = len(fields)
+			fields = append(fields, field)
+		}
+	}
+	walk(t, nil, 0, make(map[reflect.Type]bool))
+
+	structFieldsCache.Store(t, fields)
+
+	return fields
+}
+
+// fieldByIndex returns the field of v, or an invalid value if an embedded pointer on the way is nil
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+// fieldByIndexAlloc returns the field of v, allocating embedded pointers on the way
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+func parseTagOptions(tag string) (string, map[string]bool) {
+	parts := strings.Split(tag, ",")
+	options := make(map[string]bool)
+	for _, option := range parts[1:] {
+		options[strings.TrimSpace(option)] = true
+	}
+
+	return parts[0], options
+}
+
+func isBareType(t reflect.Type) bool {
+	return t == reflect.TypeOf(Object{}) || t == reflect.TypeOf(User{}) || t == reflect.TypeOf(Role{})
+}
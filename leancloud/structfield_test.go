@@ -0,0 +1,102 @@
+package leancloud
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type testAddress struct {
+	City   string `json:"city"`
+	Street string `json:"street,omitempty"`
+}
+
+type TestAudit struct {
+	Version int    `json:"version" lc:",readonly"`
+	Note    string `json:"note"`
+}
+
+type testProfile struct {
+	Object
+	testAddress
+	*TestAudit
+	Name     string `json:"name,omitempty"`
+	Nickname string `lc:"nick"`
+	Secret   string `json:"-"`
+	City     string `json:"city"`
+	hidden   string
+}
+
+func TestStructFields(t *testing.T) {
+	fields := structFields(reflect.TypeOf(testProfile{}))
+
+	names := make(map[string][]int)
+	for _, field := range fields {
+		names[field.name] = field.index
+	}
+
+	want := map[string][]int{
+		"city":    {6},
+		"street":  {1, 1},
+		"version": {2, 0},
+		"note":    {2, 1},
+		"name":    {3},
+		"nick":    {4},
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("unexpected fields %v", names)
+	}
+}
+
+func TestStructTagEncode(t *testing.T) {
+	profile := testProfile{
+		testAddress: testAddress{City: "ignored", Street: "Main St"},
+		TestAudit:   &TestAudit{Version: 3, Note: "checked"},
+		Nickname:    "tom",
+		Secret:      "secret",
+		City:        "Beijing",
+	}
+
+	encoded, err := json.Marshal(encodeObject(&profile, false, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `{"city":"Beijing","nick":"tom","note":"checked","street":"Main St"}` {
+		t.Fatalf("unexpected encoded %s", string(encoded))
+	}
+
+	encoded, err = json.Marshal(encodeStruct(testAddress{City: "Beijing"}, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `{"city":"Beijing"}` {
+		t.Fatalf("unexpected encoded %s", string(encoded))
+	}
+}
+
+func TestStructTagBind(t *testing.T) {
+	fields := map[string]interface{}{
+		"name":    "Tom",
+		"nick":    "tom",
+		"Secret":  "secret",
+		"city":    "Beijing",
+		"street":  "Main St",
+		"version": float64(3),
+		"note":    nil,
+	}
+
+	profile := new(testProfile)
+	if err := bind(reflect.ValueOf(fields), reflect.ValueOf(profile).Elem()); err != nil {
+		t.Fatal(err)
+	}
+
+	if profile.Name != "Tom" || profile.Nickname != "tom" || profile.Secret != "" || profile.City != "Beijing" {
+		t.Fatalf("dismatch profile %v", profile)
+	}
+	if profile.Street != "Main St" || profile.testAddress.City != "" {
+		t.Fatalf("dismatch embedded address %v", profile.testAddress)
+	}
+	if profile.TestAudit == nil || profile.Version != 3 {
+		t.Fatal("read-only field of embedded pointer not bound")
+	}
+}
@@ -0,0 +1,114 @@
+package leancloud
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ThumbnailOptions describes how to process the image, in terms of imageView2 of Qiniu
+type ThumbnailOptions struct {
+	// Mode is the imageView2 mode from 0 to 5, e.g. 1 scales and crops to exactly Width x Height
+	Mode int
+
+	Width  int
+	Height int
+
+	// Quality is from 1 to 100, 0 means the default of the storage
+	Quality int
+
+	// Format converts the image, e.g. jpg, png or webp
+	Format string
+
+	// Crop scales the image to cover Width x Height then crops its center, Mode is ignored
+	Crop bool
+}
+
+// StorageImageProcessor is implemented by providers whose storage could process images by URL
+type StorageImageProcessor interface {
+	ThumbnailURL(file *File, options *ThumbnailOptions) (string, error)
+}
+
+// ThumbnailURL returns URL of the processed image, if the storage of the file supports image processing
+func (file *File) ThumbnailURL(options *ThumbnailOptions) (string, error) {
+	provider, err := getStorageProvider(file.Provider)
+	if err != nil {
+		return "", err
+	}
+
+	processor, ok := provider.(StorageImageProcessor)
+	if !ok {
+		return "", fmt.Errorf("image processing is not supported by provider %s", file.Provider)
+	}
+
+	return processor.ThumbnailURL(file, options)
+}
+
+func (provider *qiniuStorageProvider) ThumbnailURL(file *File, options *ThumbnailOptions) (string, error) {
+	return buildImageViewURL(file, options)
+}
+
+func (provider *cosStorageProvider) ThumbnailURL(file *File, options *ThumbnailOptions) (string, error) {
+	return buildImageViewURL(file, options)
+}
+
+// buildImageViewURL builds imageView2/imageMogr2 URLs, supported by both Qiniu and Tencent Cloud Infinite
+func buildImageViewURL(file *File, options *ThumbnailOptions) (string, error) {
+	if file.URL == "" {
+		return "", fmt.Errorf("unable to build thumbnail URL: url is empty")
+	}
+
+	if options == nil {
+		options = new(ThumbnailOptions)
+	}
+
+	if options.Width < 0 || options.Height < 0 {
+		return "", fmt.Errorf("unable to build thumbnail URL: invalid size %dx%d", options.Width, options.Height)
+	}
+
+	if options.Quality < 0 || options.Quality > 100 {
+		return "", fmt.Errorf("unable to build thumbnail URL: quality should be from 1 to 100 but %d", options.Quality)
+	}
+
+	// quality is q in imageView2 but quality in imageMogr2
+	var params []string
+	qualityParam := "q"
+	if options.Crop {
+		if options.Width == 0 || options.Height == 0 {
+			return "", fmt.Errorf("unable to build thumbnail URL: both width and height are required to crop")
+		}
+		params = append(params, "imageMogr2",
+			"thumbnail", fmt.Sprintf("%dx%d^", options.Width, options.Height),
+			"gravity", "center",
+			"crop", fmt.Sprintf("%dx%d", options.Width, options.Height))
+		qualityParam = "quality"
+	} else {
+		if options.Mode < 0 || options.Mode > 5 {
+			return "", fmt.Errorf("unable to build thumbnail URL: mode should be from 0 to 5 but %d", options.Mode)
+		}
+		if options.Width == 0 && options.Height == 0 {
+			return "", fmt.Errorf("unable to build thumbnail URL: width or height is required")
+		}
+		params = append(params, "imageView2", fmt.Sprint(options.Mode))
+		if options.Width != 0 {
+			params = append(params, "w", fmt.Sprint(options.Width))
+		}
+		if options.Height != 0 {
+			params = append(params, "h", fmt.Sprint(options.Height))
+		}
+	}
+
+	if options.Quality != 0 {
+		params = append(params, qualityParam, fmt.Sprint(options.Quality))
+	}
+
+	if options.Format != "" {
+		params = append(params, "format", options.Format)
+	}
+
+	separator := "?"
+	if strings.Contains(file.URL, "?") {
+		separator = "&"
+	}
+
+	return fmt.Sprint(file.URL, separator, strings.Join(params, "/")), nil
+}
@@ -0,0 +1,44 @@
+package leancloud
+
+import (
+	"testing"
+)
+
+func TestFileThumbnailURL(t *testing.T) {
+	t.Run("Qiniu", func(t *testing.T) {
+		file := &File{Provider: "qiniu", URL: "https://example.com/avatar.png"}
+		url, err := file.ThumbnailURL(&ThumbnailOptions{Mode: 2, Width: 100, Height: 80, Quality: 75, Format: "webp"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if url != "https://example.com/avatar.png?imageView2/2/w/100/h/80/q/75/format/webp" {
+			t.Fatalf("unexpected url: %s", url)
+		}
+	})
+
+	t.Run("Crop", func(t *testing.T) {
+		file := &File{Provider: "qcloud", URL: "https://example.com/avatar.png"}
+		url, err := file.ThumbnailURL(&ThumbnailOptions{Width: 100, Height: 100, Crop: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if url != "https://example.com/avatar.png?imageMogr2/thumbnail/100x100^/gravity/center/crop/100x100" {
+			t.Fatalf("unexpected url: %s", url)
+		}
+
+		url, err = file.ThumbnailURL(&ThumbnailOptions{Width: 100, Height: 100, Crop: true, Quality: 80, Format: "jpg"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if url != "https://example.com/avatar.png?imageMogr2/thumbnail/100x100^/gravity/center/crop/100x100/quality/80/format/jpg" {
+			t.Fatalf("unexpected url: %s", url)
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		file := &File{Provider: "s3", URL: "https://example.com/avatar.png"}
+		if _, err := file.ThumbnailURL(&ThumbnailOptions{Width: 100}); err == nil {
+			t.Fatal("image processing should not be supported by S3")
+		}
+	})
+}
@@ -0,0 +1,261 @@
+package leancloud
+
+// TypedClass is a Class whose objects are of type T, which should be a struct embedding Object
+type TypedClass[T any] struct {
+	*Class
+}
+
+// TypedObjectRef is an ObjectRef fetching objects of type T
+type TypedObjectRef[T any] struct {
+	*ObjectRef
+}
+
+// TypedQuery is a Query returning results of type T.
+// Conditions modify the query in place and return the TypedQuery, as those of Query do
+type TypedQuery[T any] struct {
+	*Query
+}
+
+// NewTypedClass constructs a TypedClass of the class named name
+func NewTypedClass[T any](client *Client, name string) *TypedClass[T] {
+	return &TypedClass[T]{
+		Class: client.Class(name),
+	}
+}
+
+// NewTypedQuery wraps the query to return results of type T
+func NewTypedQuery[T any](query *Query) *TypedQuery[T] {
+	return &TypedQuery[T]{
+		Query: query,
+	}
+}
+
+// ID constructs a typed reference with objectId
+func (ref *TypedClass[T]) ID(id string) *TypedObjectRef[T] {
+	return &TypedObjectRef[T]{
+		ObjectRef: ref.Class.ID(id),
+	}
+}
+
+// Create writes the object to the Storage and returns its typed reference
+func (ref *TypedClass[T]) Create(object *T, authOptions ...AuthOption) (*TypedObjectRef[T], error) {
+	objectRef, err := ref.Class.Create(object, authOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedObjectRef[T]{
+		ObjectRef: objectRef,
+	}, nil
+}
+
+// NewQuery constructs a TypedQuery of the class
+func (ref *TypedClass[T]) NewQuery() *TypedQuery[T] {
+	return NewTypedQuery[T](ref.Class.NewQuery())
+}
+
+// IncludeACL returns a copy of the reference which fetches the object with its ACL
+func (ref *TypedObjectRef[T]) IncludeACL() *TypedObjectRef[T] {
+	return &TypedObjectRef[T]{
+		ObjectRef: ref.ObjectRef.IncludeACL(),
+	}
+}
+
+// Get fetches the referred object
+func (ref *TypedObjectRef[T]) Get(authOptions ...AuthOption) (*T, error) {
+	object := new(T)
+	if err := ref.ObjectRef.Get(object, authOptions...); err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// Find fetches results of the query
+func (q *TypedQuery[T]) Find(authOptions ...AuthOption) ([]T, error) {
+	var objects []T
+	if err := q.Query.Find(&objects, authOptions...); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// First fetches the first result of the query, returns nil if there is no result
+func (q *TypedQuery[T]) First(authOptions ...AuthOption) (*T, error) {
+	query := *q.Query
+	query.limit = 1
+
+	var objects []T
+	if err := query.Find(&objects, authOptions...); err != nil {
+		return nil, err
+	}
+
+	if len(objects) == 0 {
+		return nil, nil
+	}
+
+	return &objects[0], nil
+}
+
+// Conditions of the embedded Query are wrapped to return the TypedQuery, so they could be chained before Find and First
+
+func (q *TypedQuery[T]) Skip(count int) *TypedQuery[T] {
+	q.Query.Skip(count)
+	return q
+}
+
+func (q *TypedQuery[T]) Limit(limit int) *TypedQuery[T] {
+	q.Query.Limit(limit)
+	return q
+}
+
+func (q *TypedQuery[T]) Order(keys ...string) *TypedQuery[T] {
+	q.Query.Order(keys...)
+	return q
+}
+
+func (q *TypedQuery[T]) Or(queries ...*Query) *TypedQuery[T] {
+	q.Query.Or(queries...)
+	return q
+}
+
+func (q *TypedQuery[T]) And(queries ...*Query) *TypedQuery[T] {
+	q.Query.And(queries...)
+	return q
+}
+
+func (q *TypedQuery[T]) Near(key string, point *GeoPoint) *TypedQuery[T] {
+	q.Query.Near(key, point)
+	return q
+}
+
+func (q *TypedQuery[T]) WithinGeoBox(key string, southwest *GeoPoint, northeast *GeoPoint) *TypedQuery[T] {
+	q.Query.WithinGeoBox(key, southwest, northeast)
+	return q
+}
+
+func (q *TypedQuery[T]) WithinKilometers(key string, point *GeoPoint, distance float64) *TypedQuery[T] {
+	q.Query.WithinKilometers(key, point, distance)
+	return q
+}
+
+func (q *TypedQuery[T]) WithinMiles(key string, point *GeoPoint, distance float64) *TypedQuery[T] {
+	q.Query.WithinMiles(key, point, distance)
+	return q
+}
+
+func (q *TypedQuery[T]) WithinRadians(key string, point *GeoPoint, distance float64) *TypedQuery[T] {
+	q.Query.WithinRadians(key, point, distance)
+	return q
+}
+
+func (q *TypedQuery[T]) WithinPolygon(key string, vertices ...*GeoPoint) *TypedQuery[T] {
+	q.Query.WithinPolygon(key, vertices...)
+	return q
+}
+
+func (q *TypedQuery[T]) WithinCenterSphere(key string, center *GeoPoint, radians float64) *TypedQuery[T] {
+	q.Query.WithinCenterSphere(key, center, radians)
+	return q
+}
+
+func (q *TypedQuery[T]) Include(keys ...string) *TypedQuery[T] {
+	q.Query.Include(keys...)
+	return q
+}
+
+func (q *TypedQuery[T]) Select(keys ...string) *TypedQuery[T] {
+	q.Query.Select(keys...)
+	return q
+}
+
+func (q *TypedQuery[T]) MatchesQuery(key string, query *Query) *TypedQuery[T] {
+	q.Query.MatchesQuery(key, query)
+	return q
+}
+
+func (q *TypedQuery[T]) NotMatchesQuery(key string, query *Query) *TypedQuery[T] {
+	q.Query.NotMatchesQuery(key, query)
+	return q
+}
+
+func (q *TypedQuery[T]) MatchesKeyQuery(key, queryKey string, query *Query) *TypedQuery[T] {
+	q.Query.MatchesKeyQuery(key, queryKey, query)
+	return q
+}
+
+func (q *TypedQuery[T]) EqualTo(key string, value interface{}) *TypedQuery[T] {
+	q.Query.EqualTo(key, value)
+	return q
+}
+
+func (q *TypedQuery[T]) NotEqualTo(key string, value interface{}) *TypedQuery[T] {
+	q.Query.NotEqualTo(key, value)
+	return q
+}
+
+func (q *TypedQuery[T]) Exists(key string) *TypedQuery[T] {
+	q.Query.Exists(key)
+	return q
+}
+
+func (q *TypedQuery[T]) NotExists(key string) *TypedQuery[T] {
+	q.Query.NotExists(key)
+	return q
+}
+
+func (q *TypedQuery[T]) GreaterThan(key string, value interface{}) *TypedQuery[T] {
+	q.Query.GreaterThan(key, value)
+	return q
+}
+
+func (q *TypedQuery[T]) GreaterThanOrEqualTo(key string, value interface{}) *TypedQuery[T] {
+	q.Query.GreaterThanOrEqualTo(key, value)
+	return q
+}
+
+func (q *TypedQuery[T]) LessThan(key string, value interface{}) *TypedQuery[T] {
+	q.Query.LessThan(key, value)
+	return q
+}
+
+func (q *TypedQuery[T]) LessThanOrEqualTo(key string, value interface{}) *TypedQuery[T] {
+	q.Query.LessThanOrEqualTo(key, value)
+	return q
+}
+
+func (q *TypedQuery[T]) In(key string, data interface{}) *TypedQuery[T] {
+	q.Query.In(key, data)
+	return q
+}
+
+func (q *TypedQuery[T]) NotIn(key string, data interface{}) *TypedQuery[T] {
+	q.Query.NotIn(key, data)
+	return q
+}
+
+func (q *TypedQuery[T]) Regexp(key, expr, options string) *TypedQuery[T] {
+	q.Query.Regexp(key, expr, options)
+	return q
+}
+
+func (q *TypedQuery[T]) Contains(key, substring string) *TypedQuery[T] {
+	q.Query.Contains(key, substring)
+	return q
+}
+
+func (q *TypedQuery[T]) ContainsAll(key string, objects interface{}) *TypedQuery[T] {
+	q.Query.ContainsAll(key, objects)
+	return q
+}
+
+func (q *TypedQuery[T]) StartsWith(key, prefix string) *TypedQuery[T] {
+	q.Query.StartsWith(key, prefix)
+	return q
+}
+
+func (q *TypedQuery[T]) IncludeACL() *TypedQuery[T] {
+	q.Query.IncludeACL()
+	return q
+}
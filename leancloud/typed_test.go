@@ -0,0 +1,76 @@
+package leancloud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTypedClass(t *testing.T) {
+	var wheres []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		wheres = append(wheres, r.URL.Query().Get("where"))
+		switch r.URL.Path {
+		case "/1.1/classes/Staff/staff1":
+			w.Write([]byte(`{"objectId":"staff1","createdAt":"2020-01-01T00:00:00.000Z","updatedAt":"2020-01-01T00:00:00.000Z","name":"Tom","age":20}`))
+		case "/1.1/classes/Staff":
+			if r.URL.Query().Get("limit") == "1" {
+				w.Write([]byte(`{"results":[]}`))
+				return
+			}
+			w.Write([]byte(`{"results":[{"objectId":"staff1","createdAt":"2020-01-01T00:00:00.000Z","updatedAt":"2020-01-01T00:00:00.000Z","name":"Tom","age":20},{"objectId":"staff2","createdAt":"2020-01-01T00:00:00.000Z","updatedAt":"2020-01-01T00:00:00.000Z","name":"Jerry","age":18}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":404,"error":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(&ClientOptions{
+		AppID:     "app",
+		AppKey:    "key",
+		ServerURL: server.URL,
+	})
+	staffs := NewTypedClass[Staff](client, "Staff")
+
+	staff, err := staffs.ID("staff1").Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staff.ID != "staff1" || staff.Name != "Tom" || staff.Age != 20 {
+		t.Fatalf("dismatch staff %v", staff)
+	}
+
+	// conditions are chained into the typed Find and First
+	query := staffs.NewQuery().GreaterThan("age", 10).Limit(2)
+	results, err := query.Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[1].Name != "Jerry" {
+		t.Fatalf("dismatch results %v", results)
+	}
+	if wheres[len(wheres)-1] != `{"age":{"$gt":10}}` {
+		t.Fatalf("dismatch where %s", wheres[len(wheres)-1])
+	}
+
+	first, err := query.First()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != nil {
+		t.Fatalf("unexpected result %v", first)
+	}
+	if query.limit != 2 {
+		t.Fatal("query modified by First")
+	}
+
+	first, err = staffs.NewQuery().EqualTo("name", "Jerry").Order("-age").First()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != nil || wheres[len(wheres)-1] != `{"name":"Jerry"}` {
+		t.Fatalf("dismatch first %v of where %s", first, wheres[len(wheres)-1])
+	}
+}
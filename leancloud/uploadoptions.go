@@ -0,0 +1,65 @@
+package leancloud
+
+import (
+	"context"
+	"io"
+)
+
+// UploadOptions controls the progress reporting and cancellation of an upload
+type UploadOptions struct {
+	// Context cancels the upload when done, LeanCloud will be notified that the upload failed
+	Context context.Context
+
+	// OnProgress is called with bytes sent and total bytes of the file as the upload proceeds, total is -1 if unknown
+	OnProgress func(sent, total int64)
+
+	// Checksum computes MD5 and SHA-256 of the file and records them in metadata, ahead of the upload
+	// if the reader could be read again, otherwise while uploading
+	Checksum bool
+}
+
+func (options *UploadOptions) context() context.Context {
+	if options == nil || options.Context == nil {
+		return context.Background()
+	}
+
+	return options.Context
+}
+
+func (options *UploadOptions) progress(sent, total int64) {
+	if options != nil && options.OnProgress != nil {
+		options.OnProgress(sent, total)
+	}
+}
+
+// progressReader reports bytes read from the underlying reader and stops once the context is done
+type progressReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	sent    int64
+	total   int64
+	options *UploadOptions
+}
+
+func newProgressReader(reader io.Reader, total int64, options *UploadOptions) *progressReader {
+	return &progressReader{
+		ctx:     options.context(),
+		reader:  reader,
+		total:   total,
+		options: options,
+	}
+}
+
+func (reader *progressReader) Read(p []byte) (int, error) {
+	if err := reader.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := reader.reader.Read(p)
+	if n > 0 {
+		reader.sent += int64(n)
+		reader.options.progress(reader.sent, reader.total)
+	}
+
+	return n, err
+}
@@ -0,0 +1,153 @@
+package leancloud
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressReader(t *testing.T) {
+	t.Run("Progress", func(t *testing.T) {
+		var sent, total int64
+		reader := newProgressReader(bytes.NewReader([]byte("temporary file's content")), 24, &UploadOptions{
+			OnProgress: func(s, t int64) {
+				sent, total = s, t
+			},
+		})
+
+		if _, err := ioutil.ReadAll(reader); err != nil {
+			t.Fatal(err)
+		}
+
+		if sent != 24 || total != 24 {
+			t.Fatalf("unexpected progress: %d/%d", sent, total)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		reader := newProgressReader(bytes.NewReader([]byte("temporary file's content")), 24, &UploadOptions{
+			Context: ctx,
+		})
+
+		if _, err := ioutil.ReadAll(reader); err != context.Canceled {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestUploadCancel(t *testing.T) {
+	t.Run("Upload", func(t *testing.T) {
+		server := newUploadServer(t, "s3", "/storage/key/test.bin", func(w http.ResponseWriter, r *http.Request) {
+			ioutil.ReadAll(r.Body)
+		})
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		options := &UploadOptions{
+			Context: ctx,
+			OnProgress: func(sent, total int64) {
+				cancel()
+			},
+		}
+
+		content := bytes.Repeat([]byte{'x'}, 1<<20)
+		err := server.client(nil).Files.UploadWithOptions(&File{Name: "test.bin"}, bytes.NewReader(content), options)
+		if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(server.callbacks, []bool{false}) {
+			t.Fatalf("failure not reported: %v", server.callbacks)
+		}
+	})
+
+	t.Run("Chunked", func(t *testing.T) {
+		var requests []string
+		server := newUploadServer(t, "s3", "/storage/key/test.bin", func(w http.ResponseWriter, r *http.Request) {
+			ioutil.ReadAll(r.Body)
+			requests = append(requests, fmt.Sprint(r.Method, " ", r.URL.RawQuery))
+			switch r.Method {
+			case "POST":
+				w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>upload1</UploadId></InitiateMultipartUploadResult>`))
+			case "PUT":
+				w.Header().Set("ETag", `"etag"`)
+			}
+		})
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		options := &ChunkedUploadOptions{
+			UploadOptions: UploadOptions{
+				Context: ctx,
+			},
+			OnChunkUploaded: func(state *UploadState) {
+				cancel()
+			},
+		}
+
+		content := bytes.Repeat([]byte{'x'}, s3MinPartSize*2)
+		client := server.client(&S3Credentials{AccessKeyID: "access", SecretAccessKey: "secret"})
+		if err := client.Files.UploadChunked(&File{Name: "test.bin"}, bytes.NewReader(content), options); err != context.Canceled {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		wantRequests := []string{"POST uploads", "PUT partNumber=1&uploadId=upload1", "DELETE uploadId=upload1"}
+		if !reflect.DeepEqual(requests, wantRequests) {
+			t.Fatalf("upload not aborted: %v", requests)
+		}
+		if !reflect.DeepEqual(server.callbacks, []bool{false}) {
+			t.Fatalf("failure not reported: %v", server.callbacks)
+		}
+	})
+
+	t.Run("Backoff", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var requests []string
+		server := newUploadServer(t, "s3", "/storage/key/test.bin", func(w http.ResponseWriter, r *http.Request) {
+			ioutil.ReadAll(r.Body)
+			requests = append(requests, fmt.Sprint(r.Method, " ", r.URL.RawQuery))
+			switch r.Method {
+			case "POST":
+				w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>upload1</UploadId></InitiateMultipartUploadResult>`))
+			case "PUT":
+				// cancelled while waiting to retry the chunk
+				time.AfterFunc(50*time.Millisecond, cancel)
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		})
+		defer server.Close()
+
+		options := &ChunkedUploadOptions{
+			UploadOptions: UploadOptions{
+				Context: ctx,
+			},
+			Retries: 3,
+		}
+
+		start := time.Now()
+		content := bytes.Repeat([]byte{'x'}, s3MinPartSize)
+		client := server.client(&S3Credentials{AccessKeyID: "access", SecretAccessKey: "secret"})
+		if err := client.Files.UploadChunked(&File{Name: "test.bin"}, bytes.NewReader(content), options); err != context.Canceled {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed >= defaultChunkRetryBackoff {
+			t.Fatalf("cancelled after %v, want before the backoff elapsed", elapsed)
+		}
+
+		wantRequests := []string{"POST uploads", "PUT partNumber=1&uploadId=upload1", "DELETE uploadId=upload1"}
+		if !reflect.DeepEqual(requests, wantRequests) {
+			t.Fatalf("upload not aborted: %v", requests)
+		}
+	})
+}
@@ -176,6 +176,33 @@ func (ref *Users) Become(sessionToken string) (*User, error) {
 	return decodeUser(respJSON)
 }
 
+// EvictSession discards the cached user of the sessionToken, the next request carrying it will be resolved by the server again.
+// The session itself is not revoked and remains valid on the server
+func (ref *Users) EvictSession(sessionToken string) {
+	if ref.c.sessionCache != nil {
+		ref.c.sessionCache.Remove(sessionToken)
+	}
+}
+
+func (ref *Users) becomeWithCache(sessionToken string) (*User, error) {
+	if ref.c.sessionCache != nil {
+		if user, ok := ref.c.sessionCache.Get(sessionToken); ok {
+			return user, nil
+		}
+	}
+
+	user, err := ref.Become(sessionToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref.c.sessionCache != nil {
+		ref.c.sessionCache.Set(sessionToken, user)
+	}
+
+	return user, nil
+}
+
 func (ref *Users) RequestEmailVerify(email string, authOptions ...AuthOption) error {
 	path := "/1.1/requestEmailVerify"
 	options := ref.c.getRequestOptions()